@@ -0,0 +1,449 @@
+package cs2log
+
+import (
+	"strconv"
+	"time"
+)
+
+// ClutchOutcome is how a clutch ended
+type ClutchOutcome string
+
+// Outcomes of a clutch, all but ClutchLost are wins
+const (
+	// ClutchElimination is a win by killing all opponents
+	ClutchElimination ClutchOutcome = "elimination"
+	// ClutchDefuse is a win by defusing the bomb
+	ClutchDefuse ClutchOutcome = "defuse"
+	// ClutchTime is a win of the CTs by running down the clock
+	ClutchTime ClutchOutcome = "time"
+	// ClutchBomb is a win of the Terrorists by the bomb exploding
+	ClutchBomb ClutchOutcome = "bomb"
+	// ClutchLost is a round the clutching player's side lost
+	ClutchLost ClutchOutcome = "lost"
+)
+
+// Clutch is a situation of a player left alone against one or more opponents
+type Clutch struct {
+	Round  int    `json:"round"`
+	Player Player `json:"player"`
+	// Opponents is the N of the 1vN, the number of opponents alive
+	// when the player became the last one alive on their side
+	Opponents int `json:"opponents"`
+	// Start is the time the player became the last one alive
+	Start time.Time `json:"start"`
+	// TimeLeft is the time left on the round clock at Start,
+	// after the bomb was planted the time left until it explodes
+	TimeLeft    time.Duration `json:"time_left"`
+	BombPlanted bool          `json:"bomb_planted"`
+	// Kills are the kills of the player from Start on
+	Kills   int           `json:"kills"`
+	Outcome ClutchOutcome `json:"outcome"`
+}
+
+// Won reports whether the player won the clutch
+func (c Clutch) Won() bool {
+	return c.Outcome != "" && c.Outcome != ClutchLost
+}
+
+// ClutchOption configures a ClutchDetector
+type ClutchOption func(*ClutchDetector)
+
+// ClutchRoundTime sets the time of a round, the default is 1:55. A
+// "mp_roundtime_defuse" or "mp_roundtime" cvar in the log overrides it.
+func ClutchRoundTime(d time.Duration) ClutchOption {
+	return func(c *ClutchDetector) {
+		c.roundTime = d
+	}
+}
+
+// ClutchBombTime sets the time from planting the bomb until it explodes,
+// the default is 40 seconds. A "mp_c4timer" cvar in the log overrides it.
+func ClutchBombTime(d time.Duration) ClutchOption {
+	return func(c *ClutchDetector) {
+		c.bombTime = d
+	}
+}
+
+// ClutchMatch configures the match the detector tracks, e.g. its number of rounds
+func ClutchMatch(opts ...MatchOption) ClutchOption {
+	return func(c *ClutchDetector) {
+		c.matchOpts = append(c.matchOpts, opts...)
+	}
+}
+
+// ClutchDetector finds the 1vN situations of a match. Players on a team when
+// the round starts are alive until they are killed, die by the bomb or
+// suicide, or disconnect. A player alone against several opponents from the
+// start of the round is in a clutch right away, a 1v1 from the start is not.
+// Events during the warmup are ignored and a restart of the game clears the
+// clutches found so far.
+//
+// A clutch is decided by the notice the round was won with. Rounds ending
+// without a notice are decided by who is alive, a clutch that can't be
+// decided that way or whose round never ended is discarded.
+type ClutchDetector struct {
+	match     *Match
+	matchOpts []MatchOption
+
+	roundTime time.Duration
+	// roundTimeDefuse is set by mp_roundtime_defuse, which takes
+	// precedence over mp_roundtime on bomb defusal maps
+	roundTimeDefuse time.Duration
+	bombTime        time.Duration
+
+	// alive are the players alive in the current round by side
+	alive map[string]map[string]Player
+	// dead are the players that died in the current round
+	dead    map[string]bool
+	start   time.Time
+	planted time.Time
+	// cur is the clutch of the current round, there is at most one
+	cur *Clutch
+
+	clutches []Clutch
+	kills    map[string]*clutchKills
+}
+
+// clutchKills are the kills of a player in clutches over the match
+type clutchKills struct {
+	player Player
+	kills  int
+}
+
+// NewClutchDetector returns a detector for a match that hasn't started yet
+func NewClutchDetector(opts ...ClutchOption) *ClutchDetector {
+	c := &ClutchDetector{
+		roundTime: 115 * time.Second,
+		bombTime:  40 * time.Second,
+		kills:     make(map[string]*clutchKills),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.match = NewMatch(c.matchOpts...)
+
+	return c
+}
+
+// Update adds the next message and returns the clutch of the round it ended,
+// if there was one
+func (c *ClutchDetector) Update(msg Message) (Clutch, bool) {
+	wasLive := c.match.roundLive
+	c.match.Update(msg)
+
+	switch msg := msg.(type) {
+	case WorldMatchStart, WorldRoundRestart:
+		c.clutches = nil
+		c.kills = make(map[string]*clutchKills)
+		c.endRound()
+		return Clutch{}, false
+
+	case ServerCvar:
+		c.updateCvar(msg.Name, msg.Value)
+		return Clutch{}, false
+
+	case TeamNotice:
+		if wasLive && !c.match.roundLive {
+			return c.roundEnd(&msg)
+		}
+		return Clutch{}, false
+
+	case WorldRoundEnd:
+		if wasLive && !c.match.roundLive {
+			return c.roundEnd(nil)
+		}
+		return Clutch{}, false
+	}
+
+	// the round stopped without ending, e.g. the game is over
+	if wasLive && !c.match.roundLive {
+		c.endRound()
+		return Clutch{}, false
+	}
+
+	if !wasLive && c.match.roundLive {
+		c.roundStart(msg.GetTime())
+	}
+
+	if c.alive == nil || c.match.warmup {
+		return Clutch{}, false
+	}
+
+	switch msg := msg.(type) {
+	case PlayerKill:
+		c.seen(msg.Attacker)
+		c.die(msg.Victim, msg.Time)
+		if c.cur != nil && playerKey(msg.Attacker) == playerKey(c.cur.Player) && msg.Attacker.Side != msg.Victim.Side {
+			c.cur.Kills++
+		}
+
+	case PlayerAttack:
+		c.seen(msg.Attacker)
+		c.seen(msg.Victim)
+
+	case PlayerKilledBomb:
+		c.die(msg.Player, msg.Time)
+
+	case PlayerKilledSuicide:
+		c.die(msg.Player, msg.Time)
+
+	case PlayerDisconnected:
+		c.die(msg.Player, msg.Time)
+
+	case PlayerBombPlanted:
+		c.planted = msg.Time
+	}
+
+	return Clutch{}, false
+}
+
+// Clutches returns the clutches found so far in the order of the rounds
+func (c *ClutchDetector) Clutches() []Clutch {
+	return append([]Clutch(nil), c.clutches...)
+}
+
+// Compare cross-checks the kills in clutches against the clutch kills of the
+// JSON statistics logged at the end of the same round and returns the players
+// that differ. Players are matched by account ID, bots and players missing
+// on either side are skipped.
+func (c *ClutchDetector) Compare(stats JSONStatistics) []ScoreMismatch {
+	byAccount := make(map[int]PlayerStatistics)
+	for _, ps := range stats.Players {
+		if ps.AccountID != 0 {
+			byAccount[ps.AccountID] = ps
+		}
+	}
+
+	// players on the server and players that clutched and left
+	players := make(map[string]Player, len(c.match.players))
+	for key, p := range c.match.players {
+		players[key] = p
+	}
+	for key, k := range c.kills {
+		if _, ok := players[key]; !ok {
+			players[key] = k.player
+		}
+	}
+
+	var mismatches []ScoreMismatch
+	for _, p := range players {
+		id, ok := accountID(p.SteamID)
+		if !ok {
+			continue
+		}
+
+		ps, ok := byAccount[id]
+		if !ok {
+			continue
+		}
+
+		derived := 0
+		if k, ok := c.kills[playerKey(p)]; ok {
+			derived = k.kills
+		}
+
+		if derived != ps.ClutchKills {
+			mismatches = append(mismatches, ScoreMismatch{p, "clutch_kills", derived, ps.ClutchKills})
+		}
+	}
+
+	sortMismatches(mismatches)
+
+	return mismatches
+}
+
+// roundStart puts every player on a team alive
+func (c *ClutchDetector) roundStart(t time.Time) {
+	c.alive = map[string]map[string]Player{
+		"CT":        make(map[string]Player),
+		"TERRORIST": make(map[string]Player),
+	}
+	c.dead = make(map[string]bool)
+	c.start = t
+	c.planted = time.Time{}
+	c.cur = nil
+
+	for key, p := range c.match.players {
+		if side, ok := c.alive[p.Side]; ok {
+			side[key] = p
+		}
+	}
+
+	for side := range c.alive {
+		if c.opponents(side) > 1 {
+			c.startClutch(side, t)
+		}
+	}
+}
+
+// seen adds a player to the living that wasn't known when the round started.
+// Such a player was alive all along, so a clutch of a teammate was none and
+// a clutch against the player was against one more opponent.
+func (c *ClutchDetector) seen(p Player) {
+	key := playerKey(p)
+	side, ok := c.alive[p.Side]
+	if !ok || c.dead[key] {
+		return
+	}
+
+	if _, known := side[key]; !known && c.cur != nil {
+		if p.Side == c.cur.Player.Side {
+			c.cur = nil
+		} else {
+			c.cur.Opponents++
+		}
+	}
+
+	side[key] = p
+}
+
+// die removes a player from the living and starts the clutch if only one
+// player is left on their side
+func (c *ClutchDetector) die(p Player, t time.Time) {
+	key := playerKey(p)
+	c.dead[key] = true
+
+	// the side in a disconnect message may be missing
+	side := p.Side
+	for s, players := range c.alive {
+		if _, ok := players[key]; ok {
+			side = s
+			delete(players, key)
+		}
+	}
+
+	c.startClutch(side, t)
+}
+
+// opponents returns the number of players alive on the other sides
+func (c *ClutchDetector) opponents(side string) int {
+	n := 0
+	for s, players := range c.alive {
+		if s != side {
+			n += len(players)
+		}
+	}
+	return n
+}
+
+// startClutch starts the clutch of the last player alive on a side
+func (c *ClutchDetector) startClutch(side string, t time.Time) {
+	if c.cur != nil || len(c.alive[side]) != 1 {
+		return
+	}
+
+	opponents := c.opponents(side)
+	if opponents == 0 {
+		return
+	}
+
+	for _, last := range c.alive[side] {
+		c.cur = &Clutch{
+			Round:       c.match.round,
+			Player:      last,
+			Opponents:   opponents,
+			Start:       t,
+			TimeLeft:    c.timeLeft(t),
+			BombPlanted: !c.planted.IsZero(),
+		}
+	}
+}
+
+// timeLeft returns the time left on the round clock or bomb timer
+func (c *ClutchDetector) timeLeft(t time.Time) time.Duration {
+	left := c.roundTime
+	if c.roundTimeDefuse > 0 {
+		left = c.roundTimeDefuse
+	}
+	left -= t.Sub(c.start)
+
+	if !c.planted.IsZero() {
+		left = c.bombTime - t.Sub(c.planted)
+	}
+
+	return max(left, 0)
+}
+
+// roundEnd decides the clutch of the round, notice is nil if the round
+// ended without one
+func (c *ClutchDetector) roundEnd(notice *TeamNotice) (Clutch, bool) {
+	cur := c.cur
+	var outcome ClutchOutcome
+	if cur != nil {
+		outcome = c.outcome(*cur, notice)
+	}
+	c.endRound()
+
+	if cur == nil || c.match.warmup || outcome == "" {
+		return Clutch{}, false
+	}
+	cur.Outcome = outcome
+
+	key := playerKey(cur.Player)
+	k, ok := c.kills[key]
+	if !ok {
+		k = &clutchKills{}
+		c.kills[key] = k
+	}
+	k.player = cur.Player
+	k.kills += cur.Kills
+
+	c.clutches = append(c.clutches, *cur)
+
+	return *cur, true
+}
+
+// outcome decides a clutch by the winner and the notice of the round, or
+// by who is alive without a notice. It returns "" if that doesn't tell.
+func (c *ClutchDetector) outcome(cur Clutch, notice *TeamNotice) ClutchOutcome {
+	if notice == nil {
+		switch {
+		case c.dead[playerKey(cur.Player)]:
+			return ClutchLost
+		case c.opponents(cur.Player.Side) == 0:
+			return ClutchElimination
+		}
+		return ""
+	}
+
+	switch {
+	case notice.Side != cur.Player.Side:
+		return ClutchLost
+	case notice.Notice == "SFUI_Notice_Bomb_Defused":
+		return ClutchDefuse
+	case notice.Notice == "SFUI_Notice_Target_Saved":
+		return ClutchTime
+	case notice.Notice == "SFUI_Notice_Target_Bombed":
+		return ClutchBomb
+	}
+	return ClutchElimination
+}
+
+func (c *ClutchDetector) endRound() {
+	c.alive = nil
+	c.dead = nil
+	c.cur = nil
+}
+
+func (c *ClutchDetector) updateCvar(name, value string) {
+	switch name {
+	case "mp_roundtime", "mp_roundtime_defuse":
+		minutes, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return
+		}
+
+		d := time.Duration(minutes * float64(time.Minute))
+		if name == "mp_roundtime" {
+			c.roundTime = d
+		} else {
+			c.roundTimeDefuse = d
+		}
+
+	case "mp_c4timer":
+		if n, err := strconv.Atoi(value); err == nil {
+			c.bombTime = time.Duration(n) * time.Second
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package cs2log
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// clutchLog is a 1v2 of Jame won by elimination and a 1v2 of Zont1x
+// with the bomb planted, followed by the JSON statistics
+var clutchLog = strings.Join([]string{
+	`World triggered "Match_Start" on "de_nuke"`,
+	`server_cvar: "mp_c4timer" "40"`,
+	`08/31/2025 - 16:29:45.000: Starting Freeze period`,
+	magixx + ` purchased "m4a1"`,
+	jame + ` purchased "awp"`,
+	zont1x + ` purchased "ak47"`,
+	kyle + ` purchased "ak47"`,
+	`08/31/2025 - 16:30:00.000: World triggered "Round_Start"`,
+	`08/31/2025 - 16:30:10.000: ` + kill(kyle, magixx, "ak47", false),
+	`08/31/2025 - 16:30:20.000: ` + kill(jame, kyle, "awp", false),
+	`08/31/2025 - 16:30:40.000: ` + zont1x + ` triggered "Planted_The_Bomb"`,
+	`08/31/2025 - 16:30:50.000: ` + kill(jame, zont1x, "awp", false),
+	`08/31/2025 - 16:30:50.000: Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "0")`,
+	`08/31/2025 - 16:30:50.000: World triggered "Round_End"`,
+	`08/31/2025 - 16:31:45.000: Starting Freeze period`,
+	`08/31/2025 - 16:32:00.000: World triggered "Round_Start"`,
+	`08/31/2025 - 16:32:30.000: ` + zont1x + ` triggered "Planted_The_Bomb"`,
+	`08/31/2025 - 16:32:40.000: ` + kill(magixx, kyle, "m4a1", false),
+	`08/31/2025 - 16:33:10.000: Team "TERRORIST" triggered "SFUI_Notice_Target_Bombed" (CT "1") (T "1")`,
+	`08/31/2025 - 16:33:10.000: World triggered "Round_End"`,
+	`JSON_BEGIN{`,
+	`"name": "round_stats",`,
+	`"round_number" : "2",`,
+	`"fields" : "accountid,team,money,kills,deaths,assists,dmg,hsp,kdr,adr,mvp,ef,ud,3k,4k,5k,clutchk,firstk,pistolk,sniperk,blindk,bombk,firedmg,uniquek,dinks,chickenk"`,
+	`"players" : {`,
+	`"player_0" : "333,3,800,2,0,0,200,0,0,100,1,0,0,0,0,0,2,0,0,0,0,0,0,0,0,0"`,
+	`"player_1" : "222,2,800,0,1,0,0,0,0,0,1,0,0,0,0,0,1,0,0,0,0,0,0,0,0,0"`,
+	`}}JSON_END`,
+}, "\n")
+
+func TestClutchDetector(t *testing.T) {
+	c := NewClutchDetector()
+
+	var resolved []Clutch
+	var stats JSONStatistics
+	for _, msg := range parseLog(t, clutchLog) {
+		if clutch, ok := c.Update(msg); ok {
+			resolved = append(resolved, clutch)
+		}
+		if s, ok := msg.(JSONStatistics); ok {
+			stats = s
+		}
+	}
+
+	expected := []Clutch{
+		{Round: 1, Player: Player{"Jame", 3, "[U:1:333]", "CT"}, Opponents: 2, TimeLeft: 105 * time.Second, Kills: 2, Outcome: ClutchElimination},
+		{Round: 2, Player: Player{"Zont1x", 4, "[U:1:222]", "TERRORIST"}, Opponents: 2, TimeLeft: 30 * time.Second, BombPlanted: true, Outcome: ClutchBomb},
+	}
+
+	clutches := c.Clutches()
+	if len(resolved) != len(expected) || len(clutches) != len(expected) {
+		t.Fatalf("Expected %d clutches, got %+v", len(expected), clutches)
+	}
+
+	for i, e := range expected {
+		e.Start = resolved[i].Start
+		if resolved[i] != e || clutches[i] != e {
+			t.Errorf("Clutch %d: expected %+v, got %+v", i, e, resolved[i])
+		}
+		if !e.Won() {
+			t.Errorf("Clutch %d: expected a win", i)
+		}
+	}
+
+	mismatches := c.Compare(stats)
+	if len(mismatches) != 1 || mismatches[0].Player.Name != "Zont1x" || mismatches[0].Derived != 0 || mismatches[0].Reported != 1 {
+		t.Errorf("Expected only the clutch kills of Zont1x to differ, got %+v", mismatches)
+	}
+}
+
+func TestClutchDetector_Lost(t *testing.T) {
+	log := strings.Join([]string{
+		`World triggered "Match_Start" on "de_nuke"`,
+		`server_cvar: "mp_roundtime_defuse" "1.5"`,
+		`Starting Freeze period`,
+		magixx + ` purchased "m4a1"`,
+		jame + ` purchased "awp"`,
+		zont1x + ` purchased "ak47"`,
+		kyle + ` purchased "ak47"`,
+		`08/31/2025 - 16:30:00.000: World triggered "Round_Start"`,
+		`08/31/2025 - 16:30:30.000: "Jame<3><[U:1:333]><CT>" disconnected (reason "Disconnect")`,
+		`08/31/2025 - 16:30:40.000: ` + kill(zont1x, magixx, "ak47", false),
+		`08/31/2025 - 16:30:40.000: Team "TERRORIST" triggered "SFUI_Notice_Terrorists_Win" (CT "0") (T "1")`,
+		`08/31/2025 - 16:30:40.000: World triggered "Round_End"`,
+	}, "\n")
+
+	c := NewClutchDetector()
+	for _, msg := range parseLog(t, log) {
+		c.Update(msg)
+	}
+
+	clutches := c.Clutches()
+	if len(clutches) != 1 {
+		t.Fatalf("Expected 1 clutch, got %+v", clutches)
+	}
+
+	clutch := clutches[0]
+	if clutch.Player.Name != "Magixx" || clutch.Opponents != 2 || clutch.TimeLeft != time.Minute || clutch.Outcome != ClutchLost || clutch.Won() {
+		t.Errorf("Expected a lost 1v2 of Magixx with a minute left, got %+v", clutch)
+	}
+}
+
+func TestClutchDetector_RoundStart(t *testing.T) {
+	log := strings.Join([]string{
+		`World triggered "Match_Start" on "de_nuke"`,
+		`Starting Freeze period`,
+		magixx + ` purchased "m4a1"`,
+		jame + ` purchased "awp"`,
+		zont1x + ` purchased "ak47"`,
+		`08/31/2025 - 16:30:00.000: World triggered "Round_Start"`,
+		`08/31/2025 - 16:30:40.000: ` + kill(zont1x, magixx, "ak47", false),
+		`08/31/2025 - 16:30:50.000: ` + kill(zont1x, jame, "ak47", false),
+		`08/31/2025 - 16:30:50.000: Team "TERRORIST" triggered "SFUI_Notice_Terrorists_Win" (CT "0") (T "1")`,
+		`08/31/2025 - 16:30:50.000: World triggered "Round_End"`,
+		`08/31/2025 - 16:31:05.000: Starting Freeze period`,
+		`08/31/2025 - 16:31:20.000: World triggered "Round_Start"`,
+		// Kyle was on the server all along, so Zont1x isn't alone
+		`08/31/2025 - 16:31:30.000: ` + attack(kyle, magixx, "ak47", 20, 80),
+		`08/31/2025 - 16:31:40.000: ` + kill(magixx, zont1x, "m4a1", false),
+		`08/31/2025 - 16:31:50.000: ` + kill(magixx, kyle, "m4a1", false),
+		`08/31/2025 - 16:31:50.000: Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "1")`,
+		`08/31/2025 - 16:31:50.000: World triggered "Round_End"`,
+	}, "\n")
+
+	c := NewClutchDetector()
+	for _, msg := range parseLog(t, log) {
+		c.Update(msg)
+	}
+
+	clutches := c.Clutches()
+	if len(clutches) != 2 {
+		t.Fatalf("Expected 2 clutches, got %+v", clutches)
+	}
+
+	clutch := clutches[0]
+	if clutch.Player.Name != "Zont1x" || clutch.Opponents != 2 || clutch.TimeLeft != 115*time.Second || clutch.Kills != 2 || clutch.Outcome != ClutchElimination {
+		t.Errorf("Expected a 1v2 of Zont1x from the start of the round, got %+v", clutch)
+	}
+
+	// the clutch starts when Zont1x dies, not with the round
+	clutch = clutches[1]
+	if clutch.Round != 2 || clutch.Player.Name != "Kyle" || clutch.Opponents != 2 || clutch.TimeLeft != 95*time.Second || clutch.Outcome != ClutchLost {
+		t.Errorf("Expected a lost 1v2 of Kyle in round 2, got %+v", clutch)
+	}
+}
+
+func TestClutchDetector_WithoutNotice(t *testing.T) {
+	round := []string{
+		`Starting Freeze period`,
+		`08/31/2025 - 16:30:00.000: World triggered "Round_Start"`,
+		`08/31/2025 - 16:30:10.000: ` + kill(kyle, magixx, "ak47", false),
+		`08/31/2025 - 16:30:20.000: ` + kill(jame, kyle, "awp", false),
+	}
+
+	tests := map[string]struct {
+		end      []string
+		expected ClutchOutcome
+	}{
+		"elimination": {[]string{
+			`08/31/2025 - 16:30:30.000: ` + kill(jame, zont1x, "awp", false),
+			`08/31/2025 - 16:30:30.000: World triggered "Round_End"`,
+		}, ClutchElimination},
+		"lost": {[]string{
+			`08/31/2025 - 16:30:30.000: ` + kill(zont1x, jame, "ak47", false),
+			`08/31/2025 - 16:30:30.000: World triggered "Round_End"`,
+		}, ClutchLost},
+		"undecided": {[]string{
+			`08/31/2025 - 16:30:30.000: World triggered "Round_End"`,
+		}, ""},
+		"restart": {[]string{
+			`08/31/2025 - 16:30:30.000: ` + kill(jame, zont1x, "awp", false),
+			`08/31/2025 - 16:30:30.000: World triggered "Restart_Round_(1_second)"`,
+		}, ""},
+	}
+
+	for name, tt := range tests {
+		lines := append([]string{
+			`World triggered "Match_Start" on "de_nuke"`,
+			magixx + ` purchased "m4a1"`,
+			jame + ` purchased "awp"`,
+			zont1x + ` purchased "ak47"`,
+			kyle + ` purchased "ak47"`,
+		}, round...)
+
+		c := NewClutchDetector()
+		var resolved []Clutch
+		for _, msg := range parseLog(t, strings.Join(append(lines, tt.end...), "\n")) {
+			if clutch, ok := c.Update(msg); ok {
+				resolved = append(resolved, clutch)
+			}
+		}
+
+		if tt.expected == "" {
+			if len(resolved) != 0 {
+				t.Errorf("%s: expected the clutch to be discarded, got %+v", name, resolved)
+			}
+			continue
+		}
+
+		if len(resolved) != 1 || resolved[0].Player.Name != "Jame" || resolved[0].Outcome != tt.expected {
+			t.Errorf("%s: expected a clutch of Jame with outcome %s, got %+v", name, tt.expected, resolved)
+		}
+	}
+}
+
+func TestClutchDetector_MatchOptions(t *testing.T) {
+	c := NewClutchDetector(ClutchMatch(MatchMaxRounds(16)))
+
+	if c.match.maxRounds != 16 {
+		t.Errorf("Expected the match options to be forwarded, got %d max rounds", c.match.maxRounds)
+	}
+}
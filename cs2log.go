@@ -11,6 +11,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
@@ -415,42 +416,11 @@ const (
 	GameOverPattern = `Game Over: (\w+) (\w+) (\w+) score (\d+):(\d+) after (\d+) min`
 )
 
-var DefaultPatterns = map[*regexp.Regexp]MessageFunc{
-	regexp.MustCompile(ServerMessagePattern):         NewServerMessage,
-	regexp.MustCompile(FreezTimeStartPattern):        NewFreezTimeStart,
-	regexp.MustCompile(WorldMatchStartPattern):       NewWorldMatchStart,
-	regexp.MustCompile(WorldRoundStartPattern):       NewWorldRoundStart,
-	regexp.MustCompile(WorldRoundRestartPattern):     NewWorldRoundRestart,
-	regexp.MustCompile(WorldRoundEndPattern):         NewWorldRoundEnd,
-	regexp.MustCompile(WorldGameCommencingPattern):   NewWorldGameCommencing,
-	regexp.MustCompile(TeamScoredPattern):            NewTeamScored,
-	regexp.MustCompile(TeamNoticePattern):            NewTeamNotice,
-	regexp.MustCompile(PlayerConnectedPattern):       NewPlayerConnected,
-	regexp.MustCompile(PlayerDisconnectedPattern):    NewPlayerDisconnected,
-	regexp.MustCompile(PlayerEnteredPattern):         NewPlayerEntered,
-	regexp.MustCompile(PlayerBannedPattern):          NewPlayerBanned,
-	regexp.MustCompile(PlayerSwitchedPattern):        NewPlayerSwitched,
-	regexp.MustCompile(PlayerSayPattern):             NewPlayerSay,
-	regexp.MustCompile(PlayerPurchasePattern):        NewPlayerPurchase,
-	regexp.MustCompile(PlayerKillPattern):            NewPlayerKill,
-	regexp.MustCompile(PlayerKillAssistPattern):      NewPlayerKillAssist,
-	regexp.MustCompile(PlayerFlashAssistPattern):     NewPlayerFlashAssist,
-	regexp.MustCompile(PlayerAttackPattern):          NewPlayerAttack,
-	regexp.MustCompile(PlayerKilledBombPattern):      NewPlayerKilledBomb,
-	regexp.MustCompile(PlayerKilledSuicidePattern):   NewPlayerKilledSuicide,
-	regexp.MustCompile(PlayerPickedUpPattern):        NewPlayerPickedUp,
-	regexp.MustCompile(PlayerDroppedPattern):         NewPlayerDropped,
-	regexp.MustCompile(PlayerMoneyChangePattern):     NewPlayerMoneyChange,
-	regexp.MustCompile(PlayerBombGotPattern):         NewPlayerBombGot,
-	regexp.MustCompile(PlayerBombPlantedPattern):     NewPlayerBombPlanted,
-	regexp.MustCompile(PlayerBombDroppedPattern):     NewPlayerBombDropped,
-	regexp.MustCompile(PlayerBombBeginDefusePattern): NewPlayerBombBeginDefuse,
-	regexp.MustCompile(PlayerBombDefusedPattern):     NewPlayerBombDefused,
-	regexp.MustCompile(PlayerThrewPattern):           NewPlayerThrew,
-	regexp.MustCompile(PlayerBlindedPattern):         NewPlayerBlinded,
-	regexp.MustCompile(ProjectileSpawnedPattern):     NewProjectileSpawned,
-	regexp.MustCompile(GameOverPattern):              NewGameOver,
-}
+// DefaultPatterns holds the original patterns, see DefaultRegistry for an ordered
+// and independent copy. When several patterns match, the order of the built-in
+// registries decides which message is returned. Changes to the map take effect
+// right away.
+var DefaultPatterns = DefaultRegistry().Map()
 
 // Parse parses a plain log message and returns
 // message type or error if there's no match
@@ -460,7 +430,8 @@ func Parse(line string) (Message, error) {
 
 // Parse attempts to match a plain log message against the map of provided patterns,
 // if the line matches a key from the map, the corresponding MessageFunc is called on the line to
-// parse it into a Message
+// parse it into a Message. Patterns are tried in the order of the built-in registries and
+// unknown patterns in a fixed order, so a line always yields the same message type.
 func ParseWithPatterns(line string, patterns map[*regexp.Regexp]MessageFunc) (Message, error) {
 	// pattern for date, beginning of a log message
 	result := LogLinePattern.FindStringSubmatch(line)
@@ -478,10 +449,16 @@ func ParseWithPatterns(line string, patterns map[*regexp.Regexp]MessageFunc) (Me
 		return nil, err
 	}
 
-	// check all patterns, return if a pattern matches
-	for re, fun := range patterns {
+	// check all patterns in priority order, return if a pattern matches
+	for _, re := range patternKeys(patterns) {
+		// skip patterns whose literal text is missing in the message
+		if lit := literalOf(re); lit != "" && !strings.Contains(result[2], lit) {
+			continue
+		}
+
+		// the handler is looked up in the map, which may have changed
 		if result := re.FindStringSubmatch(result[2]); result != nil {
-			return fun(ti, result), nil
+			return patterns[re](ti, result), nil
 		}
 	}
 
@@ -493,12 +470,17 @@ func ParseWithPatterns(line string, patterns map[*regexp.Regexp]MessageFunc) (Me
 // ToJSON marshals messages to JSON without escaping html
 func ToJSON(m Message) string {
 	buf := &bytes.Buffer{}
-	enc := json.NewEncoder(buf)
-	enc.SetEscapeHTML(false)
-	enc.Encode(m)
+	newJSONEncoder(buf).Encode(m)
 	return buf.String()
 }
 
+// newJSONEncoder returns an encoder that doesn't escape html like ToJSON
+func newJSONEncoder(w io.Writer) *json.Encoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc
+}
+
 func NewMeta(ti time.Time, ty string) Meta {
 	return Meta{
 		Time: ti,
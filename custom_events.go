@@ -17,10 +17,18 @@ type PlayerValidated struct {
 	Player Player `json:"player"`
 }
 
+// PlayerNameChanged is received when a player changes their name,
+// Player holds the old name
+type PlayerNameChanged struct {
+	Meta
+	Player Player `json:"player"`
+	Name   string `json:"name"`
+}
+
 // PlayerAccolade is received when a player gets an achievement/award
 type PlayerAccolade struct {
 	Meta
-	Type    string  `json:"type"`     // "3k", "4k", "5k", "mvp", etc.
+	Type    string  `json:"accolade"` // "3k", "4k", "5k", "mvp", etc., "type" holds the message type
 	Player  Player  `json:"player"`
 	Value   float64 `json:"value"`
 	IsFinal bool    `json:"is_final"` // FINAL vs ROUND
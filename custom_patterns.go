@@ -1,7 +1,6 @@
 package cs2log
 
 import (
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -14,6 +13,7 @@ const (
 	
 	// Validation Events
 	PlayerValidatedPattern = `"(.+?)<(\d+)><(.+?)><>" STEAM USERID validated`
+	PlayerNameChangedPattern = `"(.+?)<(\d+)><(.+?)><(.*?)>" changed name to "(.*)"`
 	
 	// Achievement/Award Events - handle tabs or commas as delimiters
 	PlayerAccoladePattern = `ACCOLADE, (FINAL|ROUND): \{(.+?)\}[,\t]\s*(.+?)<(\d+)>[,\t]\s*VALUE: ([^,\t]+)`
@@ -111,6 +111,14 @@ func NewPlayerValidated(ti time.Time, r []string) Message {
 	}
 }
 
+func NewPlayerNameChanged(ti time.Time, r []string) Message {
+	return PlayerNameChanged{
+		Meta:   NewMeta(ti, "PlayerNameChanged"),
+		Player: NewPlayer(r[1], r[2], r[3], r[4]),
+		Name:   r[5],
+	}
+}
+
 func NewPlayerAccolade(ti time.Time, r []string) Message {
 	value, _ := strconv.ParseFloat(r[5], 64)
 	playerName := r[3]
@@ -377,74 +385,6 @@ func NewPlayer(name, id, steamID, side string) Player {
 	}
 }
 
-// ExtendedPatterns contains all custom patterns
-var ExtendedPatterns = map[*regexp.Regexp]MessageFunc{
-	// Buy Zone
-	regexp.MustCompile(PlayerLeftBuyzonePattern): NewPlayerLeftBuyzone,
-	
-	// Validation
-	regexp.MustCompile(PlayerValidatedPattern): NewPlayerValidated,
-	
-	// Achievements
-	regexp.MustCompile(PlayerAccoladePattern): NewPlayerAccolade,
-	
-	// Match Status
-	regexp.MustCompile(MatchStatusScorePattern): NewMatchStatus,
-	regexp.MustCompile(TeamPlayingPattern):      NewTeamPlaying,
-	regexp.MustCompile(MatchStatusTeamPattern):  NewTeamPlaying,
-	
-	// Pause Events
-	regexp.MustCompile(MatchPauseEnabledPattern):  NewMatchPauseEnabled,
-	regexp.MustCompile(MatchPauseDisabledPattern): NewMatchPauseDisabled,
-	regexp.MustCompile(MatchUnpausePattern):       NewMatchUnpause,
-	
-	// Debug
-	regexp.MustCompile(GrenadeThrowDebugPattern): NewGrenadeThrowDebug,
-	
-	// Server Config
-	regexp.MustCompile(ServerCvarPattern): NewServerCvar,
-	regexp.MustCompile(MpCvarPattern):     NewServerCvar,
-	
-	// RCON
-	regexp.MustCompile(RconCommandPattern): NewRconCommand,
-	
-	// Map Events
-	regexp.MustCompile(LoadingMapPattern): NewLoadingMap,
-	regexp.MustCompile(StartedMapPattern): NewStartedMap,
-	
-	// Log File
-	regexp.MustCompile(LogFileStartedPattern): NewLogFileStarted,
-	regexp.MustCompile(LogFileClosedPattern):  NewLogFileClosed,
-	
-	// Round Statistics
-	regexp.MustCompile(RoundStatsFieldsPattern): NewRoundStatsFields,
-	regexp.MustCompile(RoundStatsPlayerPattern): NewRoundStatsPlayer,
-	
-	// Chat Commands
-	regexp.MustCompile(ChatCommandPattern): NewChatCommand,
-	
-	// Game Over Detailed
-	regexp.MustCompile(GameOverDetailedPattern): NewGameOverDetailed,
-	
-	// Triggered Events
-	regexp.MustCompile(TriggeredEventPattern): NewTriggeredEvent,
-	
-	// Bomb Events
-	regexp.MustCompile(BombBeginPlantPattern):     NewBombBeginPlant,
-	regexp.MustCompile(BombPlantedTriggerPattern): NewBombBeginPlant,
-	regexp.MustCompile(BombDefusedTriggerPattern): NewBombBeginPlant,
-	
-	// Freeze Period
-	regexp.MustCompile(FreezePeriodStartPattern): NewFreezePeriodStart,
-	regexp.MustCompile(FreezePeriodEndPattern):   NewFreezePeriodEnd,
-	
-	// JSON Stats
-	regexp.MustCompile(StatsJSONStartPattern): NewStatsJSONStart,
-	regexp.MustCompile(StatsJSONEndPattern):   NewStatsJSONEnd,
-	
-	// NOTE: ServerMap and ServerName patterns removed - they're part of JSON blocks
-	
-	// Warmup Events
-	regexp.MustCompile(WarmupStartPattern): NewWarmupStart,
-	regexp.MustCompile(WarmupEndPattern):   NewWarmupEnd,
-}
\ No newline at end of file
+// ExtendedPatterns contains all custom patterns, see ExtendedRegistry
+// for an ordered and independent copy
+var ExtendedPatterns = ExtendedRegistry().Map()
@@ -0,0 +1,178 @@
+package cs2log
+
+import "time"
+
+// DamageOption configures AnalyzeDamage
+type DamageOption func(*damageConfig)
+
+type damageConfig struct {
+	weapons *WeaponRegistry
+}
+
+// DamageWeapons sets the registry weapon names are normalized with,
+// the default is DefaultWeapons
+func DamageWeapons(r *WeaponRegistry) DamageOption {
+	return func(c *damageConfig) {
+		c.weapons = r
+	}
+}
+
+// HitgroupDamage is the damage dealt to a hitgroup
+type HitgroupDamage struct {
+	Hits   int `json:"hits"`
+	Damage int `json:"damage"`
+}
+
+// DamageStats sums up attacks
+type DamageStats struct {
+	Hits int `json:"hits"`
+	// Damage is the health damage, capped at the health the victim had left
+	Damage int `json:"damage"`
+	// Overkill is the damage beyond the health the victim had left
+	Overkill    int `json:"overkill"`
+	ArmorDamage int `json:"armor_damage"`
+	// Hitgroups holds the health damage by hitgroup, e.g. "head"
+	Hitgroups map[string]HitgroupDamage `json:"hitgroups"`
+}
+
+func (s *DamageStats) add(e DamageEvent) {
+	s.Hits++
+	s.Damage += e.Damage
+	s.Overkill += e.Overkill
+	s.ArmorDamage += e.ArmorDamage
+
+	if s.Hitgroups == nil {
+		s.Hitgroups = make(map[string]HitgroupDamage)
+	}
+	h := s.Hitgroups[e.Hitgroup]
+	h.Hits++
+	h.Damage += e.Damage
+	s.Hitgroups[e.Hitgroup] = h
+}
+
+// PlayerDamage holds the damage a player dealt and received
+type PlayerDamage struct {
+	Player Player `json:"player"`
+	// Dealt is the damage dealt to enemies
+	Dealt DamageStats `json:"dealt"`
+	// Received is the damage received from anyone, also from teammates
+	// and the player themselves
+	Received DamageStats `json:"received"`
+	// TeamDamage is the damage dealt to teammates
+	TeamDamage DamageStats `json:"team_damage"`
+	// Weapons holds the damage dealt to enemies by weapon ID
+	Weapons map[string]DamageStats `json:"weapons"`
+}
+
+// DamageEvent is a single attack
+type DamageEvent struct {
+	Time time.Time `json:"time"`
+	// Offset is the time since the round went live
+	Offset   time.Duration `json:"offset"`
+	Attacker Player        `json:"attacker"`
+	Victim   Player        `json:"victim"`
+	// Weapon is the weapon ID
+	Weapon   string `json:"weapon"`
+	Hitgroup string `json:"hitgroup"`
+	// Damage is the health damage, capped at the health the victim had left
+	Damage      int `json:"damage"`
+	Overkill    int `json:"overkill"`
+	ArmorDamage int `json:"armor_damage"`
+	// Health is the health the victim has left
+	Health int `json:"health"`
+	// Team is set if attacker and victim are on the same side, also if
+	// the player hurt themselves
+	Team bool `json:"team"`
+}
+
+// RoundDamage holds the attacks of a round in log order
+type RoundDamage struct {
+	Round    int           `json:"round"`
+	Timeline []DamageEvent `json:"timeline"`
+}
+
+// DamageReport holds the damage of a match
+type DamageReport struct {
+	Rounds []RoundDamage `json:"rounds"`
+	// Players is keyed like the Scoreboard
+	Players map[string]PlayerDamage `json:"players"`
+	// Weapons holds the damage dealt to enemies by weapon ID
+	Weapons map[string]DamageStats `json:"weapons"`
+}
+
+// AnalyzeDamage breaks the damage of every player down by weapon and
+// hitgroup and separates real damage from overkill, see SplitRounds.
+// Damage to teammates is only counted as TeamDamage and Received.
+func AnalyzeDamage(rounds []Round, opts ...DamageOption) DamageReport {
+	cfg := damageConfig{weapons: DefaultWeapons()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	report := DamageReport{
+		Players: make(map[string]PlayerDamage),
+		Weapons: make(map[string]DamageStats),
+	}
+
+	update := func(p Player, f func(*PlayerDamage)) {
+		d := report.Players[playerKey(p)]
+		d.Player = p
+		f(&d)
+		report.Players[playerKey(p)] = d
+	}
+
+	for _, r := range rounds {
+		rd := RoundDamage{Round: r.Number}
+		health := make(healthTracker)
+
+		for _, msg := range r.Messages {
+			a, ok := msg.(PlayerAttack)
+			if !ok {
+				continue
+			}
+
+			damage := health.damage(a)
+			e := DamageEvent{
+				Time:        a.Time,
+				Offset:      a.Time.Sub(r.Start),
+				Attacker:    a.Attacker,
+				Victim:      a.Victim,
+				Weapon:      cfg.weapons.Normalize(a.Weapon),
+				Hitgroup:    a.Hitgroup,
+				Damage:      damage,
+				Overkill:    a.Damage - damage,
+				ArmorDamage: a.DamageArmor,
+				Health:      a.Health,
+				Team:        a.Attacker.Side == a.Victim.Side,
+			}
+			rd.Timeline = append(rd.Timeline, e)
+
+			update(a.Victim, func(d *PlayerDamage) { d.Received.add(e) })
+
+			if e.Team {
+				if playerKey(a.Attacker) != playerKey(a.Victim) {
+					update(a.Attacker, func(d *PlayerDamage) { d.TeamDamage.add(e) })
+				}
+				continue
+			}
+
+			update(a.Attacker, func(d *PlayerDamage) {
+				d.Dealt.add(e)
+				if d.Weapons == nil {
+					d.Weapons = make(map[string]DamageStats)
+				}
+				w := d.Weapons[e.Weapon]
+				w.add(e)
+				d.Weapons[e.Weapon] = w
+			})
+
+			w := report.Weapons[e.Weapon]
+			w.add(e)
+			report.Weapons[e.Weapon] = w
+		}
+
+		report.Rounds = append(report.Rounds, rd)
+	}
+
+	return report
+}
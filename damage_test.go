@@ -0,0 +1,63 @@
+package cs2log
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeDamage(t *testing.T) {
+	log := strings.Join([]string{
+		`World triggered "Match_Start" on "de_nuke"`,
+		`Starting Freeze period`,
+		`08/31/2025 - 16:30:00.000: World triggered "Round_Start"`,
+		`08/31/2025 - 16:30:05.000: ` + attack(magixx, zont1x, "m4a1_silencer_off", 30, 70),
+		`08/31/2025 - 16:30:06.000: ` + strings.Replace(attack(magixx, zont1x, "m4a1_silencer", 40, 30), "chest", "head", 1),
+		`08/31/2025 - 16:30:07.000: ` + attack(jame, magixx, "hegrenade", 10, 90),
+		`08/31/2025 - 16:30:08.000: ` + attack(jame, zont1x, "awp", 448, 0),
+		`08/31/2025 - 16:30:08.000: ` + kill(jame, zont1x, "awp", false),
+		`08/31/2025 - 16:30:20.000: ` + attack(kyle, magixx, "knife_t", 150, 0),
+		`08/31/2025 - 16:30:20.000: ` + kill(kyle, magixx, "knife_t", false),
+		`08/31/2025 - 16:30:30.000: Team "CT" triggered "SFUI_Notice_Target_Saved" (CT "1") (T "0")`,
+		`08/31/2025 - 16:30:30.000: World triggered "Round_End"`,
+	}, "\n")
+
+	report := AnalyzeDamage(SplitRounds(parseLog(t, log)))
+
+	if len(report.Rounds) != 1 || len(report.Rounds[0].Timeline) != 5 {
+		t.Fatalf("Expected a round with 5 attacks, got %+v", report.Rounds)
+	}
+
+	timeline := report.Rounds[0].Timeline
+	if e := timeline[2]; !e.Team || e.Offset != 7*time.Second || e.Weapon != "hegrenade" {
+		t.Errorf("Expected team damage after 7s, got %+v", e)
+	}
+	if e := timeline[3]; e.Damage != 30 || e.Overkill != 418 {
+		t.Errorf("Expected 30 damage and 418 overkill, got %+v", e)
+	}
+
+	zont1x := report.Players["[U:1:222]"]
+	if zont1x.Received.Damage != 100 || zont1x.Received.Overkill != 418 || zont1x.Received.Hits != 3 {
+		t.Errorf("Unexpected damage received by Zont1x %+v", zont1x.Received)
+	}
+
+	magixx := report.Players["[U:1:111]"]
+	if m := magixx.Weapons["m4a1_silencer"]; m.Hits != 2 || m.Damage != 70 || m.Hitgroups["head"] != (HitgroupDamage{1, 40}) {
+		t.Errorf("Unexpected damage of Magixx with the M4A1-S %+v", m)
+	}
+	if magixx.Received.Damage != 100 || magixx.Received.Overkill != 60 {
+		t.Errorf("Unexpected damage received by Magixx %+v", magixx.Received)
+	}
+
+	jame := report.Players["[U:1:333]"]
+	if jame.Dealt.Damage != 30 || jame.TeamDamage.Damage != 10 || jame.TeamDamage.Hits != 1 {
+		t.Errorf("Unexpected damage of Jame dealt %+v, to the team %+v", jame.Dealt, jame.TeamDamage)
+	}
+	if _, ok := jame.Weapons["hegrenade"]; ok {
+		t.Error("Expected team damage not to count for the weapon")
+	}
+
+	if k := report.Weapons["knife"]; k.Damage != 90 || k.Overkill != 60 {
+		t.Errorf("Unexpected knife damage %+v", k)
+	}
+}
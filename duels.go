@@ -0,0 +1,168 @@
+package cs2log
+
+import "time"
+
+// DuelOption configures AnalyzeDuels
+type DuelOption func(*duelConfig)
+
+type duelConfig struct {
+	tradeWindow time.Duration
+}
+
+// DuelTradeWindow sets how soon after a death the killer has to die for the
+// kill to count as a trade, the default is 5 seconds
+func DuelTradeWindow(d time.Duration) DuelOption {
+	return func(c *duelConfig) {
+		c.tradeWindow = d
+	}
+}
+
+// Trade is a kill avenging the death of a teammate
+type Trade struct {
+	// Kill is the kill of the player that got the first kill
+	Kill PlayerKill `json:"kill"`
+	// Traded is the kill that was avenged
+	Traded PlayerKill `json:"traded"`
+	// Delay is the time between both kills
+	Delay time.Duration `json:"delay"`
+}
+
+// RoundDuels holds the opening duel and the trades of a round
+type RoundDuels struct {
+	Round int `json:"round"`
+	// Opening is the first kill of the round, nil if nobody was killed
+	Opening *PlayerKill `json:"opening,omitempty"`
+	// Winner is the side that won the round
+	Winner string `json:"winner"`
+	// Trades are in the order of the kills they avenged
+	Trades []Trade `json:"trades"`
+}
+
+// PlayerDuels holds the opening duels and trades of a player
+type PlayerDuels struct {
+	Player        Player `json:"player"`
+	OpeningKills  int    `json:"opening_kills"`
+	OpeningDeaths int    `json:"opening_deaths"`
+	// TradeKills are kills of a player that had killed a teammate
+	TradeKills int `json:"trade_kills"`
+	// TradedDeaths are deaths avenged by a teammate
+	TradedDeaths int `json:"traded_deaths"`
+}
+
+// OpeningWinRate returns the percentage of opening duels the player won
+func (p PlayerDuels) OpeningWinRate() float64 {
+	duels := p.OpeningKills + p.OpeningDeaths
+	if duels == 0 {
+		return 0
+	}
+	return float64(p.OpeningKills) * 100 / float64(duels)
+}
+
+// SideEntries holds the opening duels of a side
+type SideEntries struct {
+	// Rounds is the number of rounds with an opening kill
+	Rounds       int `json:"rounds"`
+	OpeningKills int `json:"opening_kills"`
+	// RoundsWon is the number of rounds won after getting the opening kill
+	RoundsWon int `json:"rounds_won"`
+}
+
+// OpeningWinRate returns the percentage of opening duels the side won
+func (s SideEntries) OpeningWinRate() float64 {
+	if s.Rounds == 0 {
+		return 0
+	}
+	return float64(s.OpeningKills) * 100 / float64(s.Rounds)
+}
+
+// Conversion returns the percentage of opening kills that led to a round win
+func (s SideEntries) Conversion() float64 {
+	if s.OpeningKills == 0 {
+		return 0
+	}
+	return float64(s.RoundsWon) * 100 / float64(s.OpeningKills)
+}
+
+// DuelReport holds the opening duels and trades of a match
+type DuelReport struct {
+	Rounds []RoundDuels `json:"rounds"`
+	// Players is keyed like the Scoreboard
+	Players map[string]PlayerDuels `json:"players"`
+	// Sides is keyed by "CT" and "TERRORIST"
+	Sides map[string]SideEntries `json:"sides"`
+}
+
+// AnalyzeDuels finds the opening duel and the trades of every round, see
+// SplitRounds. Team kills are neither opening kills nor trades.
+func AnalyzeDuels(rounds []Round, opts ...DuelOption) DuelReport {
+	cfg := duelConfig{tradeWindow: 5 * time.Second}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	report := DuelReport{
+		Players: make(map[string]PlayerDuels),
+		Sides: map[string]SideEntries{
+			"CT":        {},
+			"TERRORIST": {},
+		},
+	}
+
+	update := func(p Player, f func(*PlayerDuels)) {
+		d := report.Players[playerKey(p)]
+		d.Player = p
+		f(&d)
+		report.Players[playerKey(p)] = d
+	}
+
+	for _, r := range rounds {
+		kills := roundKills(r)
+		rd := RoundDuels{Round: r.Number, Winner: r.Winner}
+
+		if len(kills) > 0 {
+			opening := kills[0]
+			rd.Opening = &opening
+
+			update(opening.Attacker, func(d *PlayerDuels) { d.OpeningKills++ })
+			update(opening.Victim, func(d *PlayerDuels) { d.OpeningDeaths++ })
+
+			for _, side := range []string{"CT", "TERRORIST"} {
+				s := report.Sides[side]
+				s.Rounds++
+				if opening.Attacker.Side == side {
+					s.OpeningKills++
+					if r.Winner == side {
+						s.RoundsWon++
+					}
+				}
+				report.Sides[side] = s
+			}
+		}
+
+		traded := tradedKills(kills, cfg.tradeWindow)
+		// a kill may avenge several deaths but is a single trade kill
+		avenging := make(map[int]bool)
+		for i := range kills {
+			j, ok := traded[i]
+			if !ok {
+				continue
+			}
+
+			rd.Trades = append(rd.Trades, Trade{
+				Kill:   kills[j],
+				Traded: kills[i],
+				Delay:  kills[j].Time.Sub(kills[i].Time),
+			})
+
+			if !avenging[j] {
+				avenging[j] = true
+				update(kills[j].Attacker, func(d *PlayerDuels) { d.TradeKills++ })
+			}
+			update(kills[i].Victim, func(d *PlayerDuels) { d.TradedDeaths++ })
+		}
+
+		report.Rounds = append(report.Rounds, rd)
+	}
+
+	return report
+}
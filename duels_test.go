@@ -0,0 +1,92 @@
+package cs2log
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeDuels(t *testing.T) {
+	report := AnalyzeDuels(SplitRounds(parseLog(t, ratingLog)))
+
+	if len(report.Rounds) != 2 {
+		t.Fatalf("Expected 2 rounds, got %d", len(report.Rounds))
+	}
+
+	first := report.Rounds[0]
+	if first.Opening == nil || first.Opening.Attacker.Name != "Kyle" || first.Opening.Victim.Name != "Magixx" {
+		t.Errorf("Expected Kyle to open round 1 against Magixx, got %+v", first.Opening)
+	}
+
+	if len(first.Trades) != 1 || first.Trades[0].Kill.Attacker.Name != "Jame" || first.Trades[0].Delay != 2*time.Second {
+		t.Errorf("Expected Jame to trade Magixx after 2s, got %+v", first.Trades)
+	}
+
+	// the kill of Zont1x came 10 seconds after Jame died
+	if trades := report.Rounds[1].Trades; len(trades) != 0 {
+		t.Errorf("Expected no trades in round 2, got %+v", trades)
+	}
+
+	expected := map[string]PlayerDuels{
+		"BOT:Kyle":  {OpeningKills: 1},
+		"[U:1:111]": {OpeningDeaths: 1, TradedDeaths: 1},
+		"[U:1:333]": {OpeningDeaths: 1, TradeKills: 1},
+		"[U:1:222]": {OpeningKills: 1},
+	}
+
+	for key, e := range expected {
+		got := report.Players[key]
+		e.Player = got.Player
+		if got != e {
+			t.Errorf("%s: expected %+v, got %+v", key, e, got)
+		}
+	}
+
+	if rate := report.Players["BOT:Kyle"].OpeningWinRate(); rate != 100 {
+		t.Errorf("Expected Kyle to win all opening duels, got %v%%", rate)
+	}
+
+	ct, tt := report.Sides["CT"], report.Sides["TERRORIST"]
+	if ct != (SideEntries{Rounds: 2}) || tt != (SideEntries{Rounds: 2, OpeningKills: 2, RoundsWon: 1}) {
+		t.Errorf("Unexpected side entries CT %+v, T %+v", ct, tt)
+	}
+
+	if tt.OpeningWinRate() != 100 || tt.Conversion() != 50 {
+		t.Errorf("Expected T to win 100%% of openings and convert 50%%, got %v and %v", tt.OpeningWinRate(), tt.Conversion())
+	}
+}
+
+func TestAnalyzeDuels_TradeWindow(t *testing.T) {
+	report := AnalyzeDuels(SplitRounds(parseLog(t, ratingLog)), DuelTradeWindow(15*time.Second))
+
+	if trades := report.Rounds[1].Trades; len(trades) != 1 || trades[0].Traded.Victim.Name != "Jame" {
+		t.Errorf("Expected Jame's death to be traded with a window of 15s, got %+v", trades)
+	}
+}
+
+func TestAnalyzeDuels_MultipleTrades(t *testing.T) {
+	ropz := `"ropz<6><[U:1:444]><CT>"`
+	messages := parseLog(t, strings.Join([]string{
+		`08/31/2025 - 16:30:00.000: World triggered "Round_Start"`,
+		`08/31/2025 - 16:30:10.000: ` + kill(zont1x, magixx, "ak47", false),
+		`08/31/2025 - 16:30:11.000: ` + kill(zont1x, jame, "ak47", false),
+		`08/31/2025 - 16:30:12.000: ` + kill(ropz, zont1x, "m4a1", true),
+	}, "\n"))
+
+	report := AnalyzeDuels([]Round{{Number: 1, Messages: messages}})
+
+	// the kill of Zont1x avenges both deaths
+	if trades := report.Rounds[0].Trades; len(trades) != 2 {
+		t.Errorf("Expected 2 trades, got %+v", trades)
+	}
+
+	if d := report.Players["[U:1:444]"]; d.TradeKills != 1 {
+		t.Errorf("Expected ropz to have 1 trade kill, got %d", d.TradeKills)
+	}
+
+	for _, key := range []string{"[U:1:111]", "[U:1:333]"} {
+		if d := report.Players[key]; d.TradedDeaths != 1 {
+			t.Errorf("%s: expected 1 traded death, got %d", key, d.TradedDeaths)
+		}
+	}
+}
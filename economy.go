@@ -0,0 +1,488 @@
+package cs2log
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BuyType classifies how much a team spent in a round
+type BuyType string
+
+// Buy types of a team
+const (
+	BuyPistol BuyType = "pistol"
+	BuyEco    BuyType = "eco"
+	BuyForce  BuyType = "force"
+	BuyHalf   BuyType = "half-buy"
+	BuyFull   BuyType = "full-buy"
+)
+
+// PriceTable holds the prices of equipment by name, the names are
+// normalized without the "weapon_" or "item_" prefix
+type PriceTable map[string]int
+
+// DefaultPrices are the prices of Counter-Strike 2 from DefaultWeapons.
+// Starting pistols count with their price, knife and bomb are free.
+var DefaultPrices = DefaultWeapons().Prices()
+
+// Value returns the total price of the items, unknown items are free
+func (t PriceTable) Value(items []string) int {
+	value := 0
+	for _, item := range items {
+		value += t[equipmentName(item)]
+	}
+	return value
+}
+
+// equipmentName normalizes the name of an item, the log names items
+// "weapon_ak47", "item_assaultsuit", "kevlar(100)" or just "ak47"
+func equipmentName(item string) string {
+	item = strings.TrimPrefix(item, "weapon_")
+	item = strings.TrimPrefix(item, "item_")
+	if i := strings.IndexByte(item, '('); i >= 0 {
+		item = item[:i]
+	}
+	return item
+}
+
+// BuyThresholds are the limits a team buy is classified by, all values are
+// averages per player
+type BuyThresholds struct {
+	// EcoValue is the equipment value below which a team is on an eco
+	EcoValue int `json:"eco_value"`
+	// FullValue is the equipment value from which on a team is on a full buy
+	FullValue int `json:"full_value"`
+	// ForceMoney is the money left after buying below which a team in
+	// between is on a force buy, otherwise it's on a half-buy
+	ForceMoney int `json:"force_money"`
+}
+
+// DefaultBuyThresholds are the thresholds used unless configured otherwise
+var DefaultBuyThresholds = BuyThresholds{EcoValue: 1500, FullValue: 4000, ForceMoney: 1000}
+
+// EconomyOption configures an Economy
+type EconomyOption func(*Economy)
+
+// EconomyPrices sets the price table equipment is valued with,
+// the default is DefaultPrices
+func EconomyPrices(prices PriceTable) EconomyOption {
+	return func(e *Economy) {
+		e.prices = prices
+	}
+}
+
+// EconomyThresholds sets the thresholds team buys are classified by,
+// the default is DefaultBuyThresholds
+func EconomyThresholds(t BuyThresholds) EconomyOption {
+	return func(e *Economy) {
+		e.thresholds = t
+	}
+}
+
+// EconomyMaxRounds sets the number of rounds in regulation, which decides
+// the pistol rounds, the default is 24. A "mp_maxrounds" cvar in the log
+// overrides it.
+func EconomyMaxRounds(n int) EconomyOption {
+	return func(e *Economy) {
+		e.match.maxRounds = n
+	}
+}
+
+// EconomyLossBonus sets the loss bonus, a team losing its first round gets
+// base, every further consecutive loss step more, up to max losses. The
+// default is 1400, 500 and 4. The cvars "cash_team_loser_bonus",
+// "cash_team_loser_bonus_consecutive_rounds" and "mp_consecutive_loss_max"
+// in the log override it.
+func EconomyLossBonus(base, step, max int) EconomyOption {
+	return func(e *Economy) {
+		e.lossBase, e.lossStep, e.lossMax = base, step, max
+	}
+}
+
+// EconomyStartMoney sets the money every player starts a half with in
+// regulation and in overtime, the default is 800 and 12500. The cvars
+// "mp_startmoney" and "mp_overtime_startmoney" in the log override it.
+func EconomyStartMoney(regulation, overtime int) EconomyOption {
+	return func(e *Economy) {
+		e.startMoney, e.overtimeMoney = regulation, overtime
+	}
+}
+
+// PlayerEconomy is the money and equipment of a player in a round
+type PlayerEconomy struct {
+	Player Player `json:"player"`
+	// StartMoney is the money when the round started
+	StartMoney int `json:"start_money"`
+	// Spent is the money spent on purchases
+	Spent int `json:"spent"`
+	// Money is the money left after buying
+	Money int `json:"money"`
+	// Equipment is the equipment the player went into the round with,
+	// sorted by name
+	Equipment      []string `json:"equipment"`
+	EquipmentValue int      `json:"equipment_value"`
+
+	// valued is set once Equipment is known
+	valued bool
+	// changed is set once the money of the player changed in the round
+	changed bool
+}
+
+// TeamEconomy is the money and buy of a side in a round
+type TeamEconomy struct {
+	Players        int     `json:"players"`
+	StartMoney     int     `json:"start_money"`
+	Spent          int     `json:"spent"`
+	Money          int     `json:"money"`
+	EquipmentValue int     `json:"equipment_value"`
+	Buy            BuyType `json:"buy"`
+	// Losses is the number of losses counting towards the loss bonus
+	Losses int `json:"losses"`
+	// LossBonus is the money every player gets if the team loses the round
+	LossBonus int `json:"loss_bonus"`
+}
+
+// RoundEconomy is the economy of both sides in a round
+type RoundEconomy struct {
+	Round  int    `json:"round"`
+	Winner string `json:"winner"`
+	// Players is keyed like the Scoreboard
+	Players map[string]PlayerEconomy `json:"players"`
+	// Sides is keyed by "CT" and "TERRORIST"
+	Sides map[string]TeamEconomy `json:"sides"`
+}
+
+// Economy tracks the money and equipment of every player and classifies
+// the buys of both sides every round. Equipment is valued when a player
+// leaves the buy zone, players that never leave it when they die or the
+// round ends. Teams start every half and overtime half with one loss
+// counting towards the loss bonus, a win takes one loss off, and players
+// with the start money.
+type Economy struct {
+	match *Match
+
+	prices     PriceTable
+	thresholds BuyThresholds
+	lossBase   int
+	lossStep   int
+	lossMax    int
+
+	startMoney    int
+	overtimeMoney int
+
+	money     map[string]int
+	inventory map[string]map[string]int
+	losses    map[string]int
+	// cur is the economy of the current round, nil between rounds
+	cur    map[string]*PlayerEconomy
+	pistol bool
+
+	rounds []RoundEconomy
+}
+
+// NewEconomy returns a tracker for a match that hasn't started yet
+func NewEconomy(opts ...EconomyOption) *Economy {
+	e := &Economy{
+		match:      NewMatch(),
+		prices:     DefaultPrices,
+		thresholds: DefaultBuyThresholds,
+		lossBase:   1400,
+		lossStep:   500,
+		lossMax:    4,
+
+		startMoney:    800,
+		overtimeMoney: 12500,
+		money:      make(map[string]int),
+		inventory:  make(map[string]map[string]int),
+		losses:     make(map[string]int),
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Update adds the next message and returns the economy of the round it ended
+func (e *Economy) Update(msg Message) (RoundEconomy, bool) {
+	wasLive := e.match.roundLive
+	roundsPlayed := e.match.roundsPlayed
+	e.match.Update(msg)
+
+	switch msg := msg.(type) {
+	case WorldMatchStart, WorldRoundRestart:
+		e.rounds = nil
+		e.inventory = make(map[string]map[string]int)
+		e.losses = make(map[string]int)
+		e.cur = nil
+		return RoundEconomy{}, false
+
+	case ServerCvar:
+		e.updateCvar(msg.Name, msg.Value)
+		return RoundEconomy{}, false
+
+	case FreezTimeStart:
+		e.freezeStart(roundsPlayed)
+		return RoundEconomy{}, false
+
+	case FreezePeriod:
+		if msg.Action == "start" {
+			e.freezeStart(roundsPlayed)
+		}
+		return RoundEconomy{}, false
+
+	case TeamNotice:
+		if wasLive && !e.match.roundLive && e.cur != nil {
+			return e.roundEnd(msg.Side), true
+		}
+		return RoundEconomy{}, false
+
+	case PlayerMoneyChange:
+		key := playerKey(msg.Player)
+		if e.cur != nil {
+			pe := e.player(msg.Player)
+			if !pe.changed {
+				// the first money change of the round tells the money before,
+				// round rewards aren't logged
+				pe.StartMoney = msg.Equation.A
+				pe.changed = true
+			}
+			if msg.Purchase != "" && msg.Equation.B < 0 {
+				pe.Spent -= msg.Equation.B
+			}
+		}
+		e.money[key] = msg.Equation.Result
+		return RoundEconomy{}, false
+	}
+
+	switch msg := msg.(type) {
+	case PlayerPurchase:
+		e.add(msg.Player, msg.Item)
+		e.revalue(msg.Player)
+
+	case PlayerPickedUp:
+		e.add(msg.Player, msg.Item)
+
+	case PlayerDropped:
+		items := e.inventory[playerKey(msg.Player)]
+		name := equipmentName(msg.Item)
+		if items[name] > 1 {
+			items[name]--
+		} else {
+			delete(items, name)
+		}
+
+	case PlayerLeftBuyzone:
+		items := make(map[string]int)
+		for _, item := range msg.Equipment {
+			items[equipmentName(item)]++
+		}
+		e.inventory[playerKey(msg.Player)] = items
+		e.value(msg.Player)
+
+	case PlayerKill:
+		e.die(msg.Victim)
+
+	case PlayerKilledBomb:
+		e.die(msg.Player)
+
+	case PlayerKilledSuicide:
+		e.die(msg.Player)
+	}
+
+	return RoundEconomy{}, false
+}
+
+// Rounds returns the economy of the rounds played so far
+func (e *Economy) Rounds() []RoundEconomy {
+	return append([]RoundEconomy(nil), e.rounds...)
+}
+
+// freezeStart starts tracking the round, roundsPlayed are the rounds
+// played before it
+func (e *Economy) freezeStart(roundsPlayed int) {
+	if e.match.warmup {
+		return
+	}
+
+	overtime := roundsPlayed >= e.match.maxRounds
+	half := e.match.newHalf()
+	e.pistol = half && !overtime
+
+	if half {
+		money := e.startMoney
+		if overtime {
+			money = e.overtimeMoney
+		}
+
+		for key := range e.money {
+			e.money[key] = money
+		}
+		for _, p := range e.match.players {
+			e.money[playerKey(p)] = money
+		}
+
+		e.inventory = make(map[string]map[string]int)
+		e.losses = map[string]int{"CT": 1, "TERRORIST": 1}
+	}
+
+	e.cur = make(map[string]*PlayerEconomy)
+	for _, p := range e.match.players {
+		if p.Side == "CT" || p.Side == "TERRORIST" {
+			e.player(p)
+		}
+	}
+}
+
+// player returns the economy of a player in the current round
+func (e *Economy) player(p Player) *PlayerEconomy {
+	key := playerKey(p)
+
+	pe, ok := e.cur[key]
+	if !ok {
+		money := e.money[key]
+		pe = &PlayerEconomy{StartMoney: money, Money: money}
+		e.cur[key] = pe
+	}
+	pe.Player = p
+
+	return pe
+}
+
+func (e *Economy) add(p Player, item string) {
+	key := playerKey(p)
+	if e.inventory[key] == nil {
+		e.inventory[key] = make(map[string]int)
+	}
+	e.inventory[key][equipmentName(item)]++
+}
+
+// value records the equipment and money left of a player
+func (e *Economy) value(p Player) {
+	if e.cur == nil {
+		return
+	}
+
+	key := playerKey(p)
+	pe := e.player(p)
+
+	pe.Equipment = nil
+	for item, n := range e.inventory[key] {
+		for i := 0; i < n; i++ {
+			pe.Equipment = append(pe.Equipment, item)
+		}
+	}
+	sort.Strings(pe.Equipment)
+
+	pe.EquipmentValue = e.prices.Value(pe.Equipment)
+	if m, ok := e.money[key]; ok {
+		pe.Money = m
+	}
+	pe.valued = true
+}
+
+// revalue updates the equipment of a player that bought after leaving the
+// buy zone, e.g. after returning to it
+func (e *Economy) revalue(p Player) {
+	if pe, ok := e.cur[playerKey(p)]; ok && pe.valued {
+		e.value(p)
+	}
+}
+
+func (e *Economy) die(p Player) {
+	if pe, ok := e.cur[playerKey(p)]; ok && !pe.valued {
+		e.value(p)
+	}
+	delete(e.inventory, playerKey(p))
+}
+
+// roundEnd classifies the buys and counts the loss bonus
+func (e *Economy) roundEnd(winner string) RoundEconomy {
+	r := RoundEconomy{
+		Round:   e.match.round,
+		Winner:  winner,
+		Players: make(map[string]PlayerEconomy, len(e.cur)),
+		Sides:   make(map[string]TeamEconomy, 2),
+	}
+
+	for key, pe := range e.cur {
+		if !pe.valued {
+			e.value(pe.Player)
+		}
+		r.Players[key] = *pe
+	}
+
+	for _, side := range []string{"CT", "TERRORIST"} {
+		t := TeamEconomy{Losses: e.losses[side]}
+		t.LossBonus = e.lossBase + e.lossStep*min(t.Losses, e.lossMax)
+
+		for _, pe := range e.cur {
+			if pe.Player.Side != side {
+				continue
+			}
+			t.Players++
+			t.StartMoney += pe.StartMoney
+			t.Spent += pe.Spent
+			t.Money += pe.Money
+			t.EquipmentValue += pe.EquipmentValue
+		}
+
+		t.Buy = e.classify(t)
+		r.Sides[side] = t
+
+		if side == winner {
+			e.losses[side] = max(t.Losses-1, 0)
+		} else {
+			e.losses[side] = min(t.Losses+1, e.lossMax)
+		}
+	}
+
+	e.cur = nil
+	e.rounds = append(e.rounds, r)
+
+	return r
+}
+
+// classify returns the buy of a team
+func (e *Economy) classify(t TeamEconomy) BuyType {
+	if t.Players == 0 {
+		return ""
+	}
+	if e.pistol {
+		return BuyPistol
+	}
+
+	value := t.EquipmentValue / t.Players
+	switch {
+	case value < e.thresholds.EcoValue:
+		return BuyEco
+	case value >= e.thresholds.FullValue:
+		return BuyFull
+	case t.Money/t.Players < e.thresholds.ForceMoney:
+		return BuyForce
+	default:
+		return BuyHalf
+	}
+}
+
+func (e *Economy) updateCvar(name, value string) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return
+	}
+
+	switch name {
+	case "cash_team_loser_bonus":
+		e.lossBase = n
+	case "cash_team_loser_bonus_consecutive_rounds":
+		e.lossStep = n
+	case "mp_consecutive_loss_max":
+		e.lossMax = n
+	case "mp_startmoney":
+		e.startMoney = n
+	case "mp_overtime_startmoney":
+		e.overtimeMoney = n
+	}
+}
@@ -0,0 +1,204 @@
+package cs2log
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// economyLog is a pistol round won by the Terrorists, a force buy of the
+// Terrorists against an eco of the CTs and the pistol round of the second half
+var economyLog = strings.Join([]string{
+	`World triggered "Match_Start" on "de_nuke"`,
+	`Starting Freeze period`,
+	magixx + ` left buyzone with [ weapon_knife weapon_usp_silencer ]`,
+	jame + ` money change 800-650 = $150 (tracked) (purchase: item_kevlar)`,
+	jame + ` purchased "item_kevlar"`,
+	jame + ` left buyzone with [ weapon_knife weapon_usp_silencer kevlar(100) ]`,
+	zont1x + ` left buyzone with [ weapon_knife weapon_glock ]`,
+	kyle + ` left buyzone with [ weapon_knife weapon_glock ]`,
+	`World triggered "Round_Start"`,
+	kill(zont1x, magixx, "glock", true),
+	`Team "TERRORIST" triggered "SFUI_Notice_Target_Bombed" (CT "0") (T "1")`,
+	`World triggered "Round_End"`,
+
+	`Starting Freeze period`,
+	zont1x + ` money change 5600-2700 = $2900 (tracked) (purchase: weapon_ak47)`,
+	zont1x + ` purchased "ak47"`,
+	zont1x + ` money change 2900-1000 = $1900 (tracked) (purchase: item_assaultsuit)`,
+	zont1x + ` purchased "item_assaultsuit"`,
+	zont1x + ` money change 1900-300 = $1600 (tracked) (purchase: weapon_smokegrenade)`,
+	zont1x + ` purchased "smokegrenade"`,
+	zont1x + ` money change 1600-200 = $1400 (tracked) (purchase: weapon_flashbang)`,
+	zont1x + ` purchased "flashbang"`,
+	zont1x + ` left buyzone with [ weapon_knife weapon_glock weapon_ak47 weapon_smokegrenade weapon_flashbang kevlar(100) helmet ]`,
+	kyle + ` money change 2850-1800 = $1050 (tracked) (purchase: weapon_galilar)`,
+	kyle + ` purchased "galilar"`,
+	kyle + ` money change 1050-650 = $400 (tracked) (purchase: item_kevlar)`,
+	kyle + ` purchased "item_kevlar"`,
+	magixx + ` left buyzone with [ weapon_knife weapon_usp_silencer ]`,
+	jame + ` left buyzone with [ weapon_knife weapon_usp_silencer kevlar(80) ]`,
+	`World triggered "Round_Start"`,
+	`Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "1")`,
+	`World triggered "Round_End"`,
+
+	`Starting Freeze period`,
+	`World triggered "Round_Start"`,
+	`Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "2") (T "1")`,
+	`World triggered "Round_End"`,
+}, "\n")
+
+func TestEconomy(t *testing.T) {
+	e := NewEconomy(EconomyMaxRounds(4))
+	for _, msg := range parseLog(t, economyLog) {
+		e.Update(msg)
+	}
+
+	rounds := e.Rounds()
+	if len(rounds) != 3 {
+		t.Fatalf("Expected 3 rounds, got %d", len(rounds))
+	}
+
+	expected := []struct {
+		buyCT, buyT       BuyType
+		lossesCT, lossesT int
+		bonusCT, bonusT   int
+	}{
+		{BuyPistol, BuyPistol, 1, 1, 1900, 1900},
+		{BuyEco, BuyForce, 2, 0, 2400, 1400},
+		{BuyPistol, BuyPistol, 1, 1, 1900, 1900},
+	}
+
+	for i, e := range expected {
+		ct, tt := rounds[i].Sides["CT"], rounds[i].Sides["TERRORIST"]
+		if ct.Buy != e.buyCT || tt.Buy != e.buyT {
+			t.Errorf("Round %d: expected %s against %s, got %s against %s", i+1, e.buyCT, e.buyT, ct.Buy, tt.Buy)
+		}
+		if ct.Losses != e.lossesCT || tt.Losses != e.lossesT || ct.LossBonus != e.bonusCT || tt.LossBonus != e.bonusT {
+			t.Errorf("Round %d: unexpected loss bonus CT %+v, T %+v", i+1, ct, tt)
+		}
+	}
+
+	zont1x := rounds[1].Players["[U:1:222]"]
+	if zont1x.StartMoney != 5600 || zont1x.Spent != 4200 || zont1x.Money != 1400 || zont1x.EquipmentValue != 4400 {
+		t.Errorf("Unexpected economy of Zont1x %+v", zont1x)
+	}
+
+	// Kyle never left the buy zone and kept the pistol of the first round
+	kyle := rounds[1].Players["BOT:Kyle"]
+	if strings.Join(kyle.Equipment, ",") != "galilar,glock,kevlar,knife" || kyle.EquipmentValue != 2650 || kyle.Money != 400 {
+		t.Errorf("Unexpected economy of Kyle %+v", kyle)
+	}
+
+	if tt := rounds[1].Sides["TERRORIST"]; tt.Players != 2 || tt.Spent != 6650 || tt.EquipmentValue != 7050 {
+		t.Errorf("Unexpected economy of the Terrorists %+v", tt)
+	}
+}
+
+func TestEconomy_Halftime(t *testing.T) {
+	lines := []string{`World triggered "Match_Start" on "de_nuke"`}
+	for i := 1; i <= 12; i++ {
+		lines = append(lines,
+			`Starting Freeze period`,
+			zont1x+` money change 9000-2700 = $6300 (tracked) (purchase: weapon_ak47)`,
+			zont1x+` purchased "ak47"`,
+			magixx+` left buyzone with [ weapon_knife weapon_usp_silencer ]`,
+			`World triggered "Round_Start"`,
+			fmt.Sprintf(`Team "TERRORIST" triggered "SFUI_Notice_Terrorists_Win" (CT "0") (T "%d")`, i),
+			`World triggered "Round_End"`,
+		)
+	}
+
+	// the pistol round of the second half, nobody buys
+	lines = append(lines,
+		`Starting Freeze period`,
+		magixx+` left buyzone with [ weapon_knife weapon_usp_silencer ]`,
+		zont1x+` left buyzone with [ weapon_knife weapon_glock ]`,
+		`World triggered "Round_Start"`,
+		`Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "12")`,
+		`World triggered "Round_End"`,
+	)
+
+	e := NewEconomy()
+	for _, msg := range parseLog(t, strings.Join(lines, "\n")) {
+		e.Update(msg)
+	}
+
+	rounds := e.Rounds()
+	if len(rounds) != 13 {
+		t.Fatalf("Expected 13 rounds, got %d", len(rounds))
+	}
+
+	if tt := rounds[11].Sides["TERRORIST"]; tt.Losses != 0 || rounds[11].Players["[U:1:222]"].StartMoney != 9000 {
+		t.Errorf("Unexpected economy of the Terrorists in round 12 %+v", rounds[11])
+	}
+
+	r := rounds[12]
+	if r.Round != 13 {
+		t.Errorf("Expected round 13, got %d", r.Round)
+	}
+
+	for _, side := range []string{"CT", "TERRORIST"} {
+		if te := r.Sides[side]; te.Buy != BuyPistol || te.Losses != 1 || te.LossBonus != 1900 || te.StartMoney != 800 {
+			t.Errorf("Expected a pistol round with $800 and one loss of %s, got %+v", side, te)
+		}
+	}
+
+	if z := r.Players["[U:1:222]"]; z.StartMoney != 800 || z.Money != 800 {
+		t.Errorf("Expected Zont1x to start the second half with $800, got %+v", z)
+	}
+}
+
+func TestEconomy_Overtime(t *testing.T) {
+	lines := []string{`World triggered "Match_Start" on "de_nuke"`, `server_cvar: "mp_overtime_startmoney" "10000"`}
+	for i := 1; i <= 5; i++ {
+		lines = append(lines,
+			`Starting Freeze period`,
+			zont1x+` left buyzone with [ weapon_knife weapon_glock ]`,
+			`World triggered "Round_Start"`,
+			fmt.Sprintf(`Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "%d") (T "0")`, i),
+			`World triggered "Round_End"`,
+		)
+	}
+
+	e := NewEconomy(EconomyMaxRounds(4))
+	for _, msg := range parseLog(t, strings.Join(lines, "\n")) {
+		e.Update(msg)
+	}
+
+	rounds := e.Rounds()
+	if len(rounds) != 5 {
+		t.Fatalf("Expected 5 rounds, got %d", len(rounds))
+	}
+
+	if tt := rounds[3].Sides["TERRORIST"]; tt.Losses != 2 || tt.StartMoney != 800 {
+		t.Errorf("Unexpected economy of the Terrorists in round 4 %+v", tt)
+	}
+
+	// the first round of overtime starts with the overtime money and no loss bonus built up
+	if tt := rounds[4].Sides["TERRORIST"]; tt.Buy == BuyPistol || tt.Losses != 1 || tt.StartMoney != 10000 {
+		t.Errorf("Expected the Terrorists to start overtime with $10000 and one loss, got %+v", tt)
+	}
+}
+
+func TestEconomy_Thresholds(t *testing.T) {
+	e := NewEconomy(
+		EconomyMaxRounds(4),
+		EconomyThresholds(BuyThresholds{EcoValue: 500, FullValue: 3500, ForceMoney: 1000}),
+		EconomyLossBonus(1000, 1000, 2),
+	)
+	for _, msg := range parseLog(t, economyLog) {
+		e.Update(msg)
+	}
+
+	r := e.Rounds()[1]
+	if ct, tt := r.Sides["CT"], r.Sides["TERRORIST"]; ct.Buy != BuyForce || tt.Buy != BuyFull || ct.LossBonus != 3000 {
+		t.Errorf("Expected a force buy with a loss bonus of 3000 against a full buy, got CT %+v, T %+v", ct, tt)
+	}
+}
+
+func TestPriceTable(t *testing.T) {
+	if v := DefaultPrices.Value([]string{"weapon_ak47", "kevlar(100)", "helmet", "item_defuser", "weapon_knife", "weapon_c4"}); v != 4100 {
+		t.Errorf("Expected a value of 4100, got %d", v)
+	}
+}
@@ -5,10 +5,9 @@ import (
 )
 
 // CombinedPatterns merges DefaultPatterns with ExtendedPatterns
-// Returns patterns in priority order (specific patterns before general ones)
+// ParseWithPatterns tries them in the priority order of CombinedRegistry
+// (specific patterns before general ones)
 func CombinedPatterns() map[*regexp.Regexp]MessageFunc {
-	// We need to use a slice to maintain order since maps don't preserve order
-	// But for now, we'll return a map and rely on ParseWithOrderedPatterns
 	combined := make(map[*regexp.Regexp]MessageFunc)
 	
 	// First add default patterns
@@ -32,6 +31,7 @@ func ParseEnhanced(line string) (Message, error) {
 }
 
 // ParseExtendedOnly parses using only the extended patterns
+// in the priority order of ExtendedRegistry
 // Useful for testing or when you only want custom events
 func ParseExtendedOnly(line string) (Message, error) {
 	return ParseWithPatterns(line, ExtendedPatterns)
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 
@@ -38,25 +37,24 @@ func main() {
 		os.Exit(1)
 	}
 
-	r := bufio.NewReader(file)
+	// scanner assembles multi-line JSON statistics blocks
+	s := cs2log.NewScanner(file)
 
-	// read first line
-	l, _, err := r.ReadLine()
+	for s.Scan() {
 
-	for err == nil {
+		r := s.Record()
 
-		// parse
-		m, errParse := cs2log.Parse(string(l))
-
-		if errParse != nil {
+		if r.Err != nil {
 			// print parse errors to stderr
-			fmt.Fprintf(os.Stderr, "ERROR: %s", cs2log.ToJSON(m))
+			fmt.Fprintf(os.Stderr, "ERROR: line %d: %s: %s\n", r.Line, r.Err, r.Raw)
 		} else {
 			// print to stdout
-			fmt.Fprintf(os.Stdout, "%s", cs2log.ToJSON(m))
+			fmt.Fprintf(os.Stdout, "%s", cs2log.ToJSON(r.Message))
 		}
+	}
 
-		// next line
-		l, _, err = r.ReadLine()
+	if err := s.Err(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,195 @@
+package cs2log
+
+import "time"
+
+// flashLinkWindow is how far apart the throw and the blinds of a flashbang
+// may be logged, the log reports both when the flashbang detonates
+const flashLinkWindow = time.Second
+
+// flashKillWindow is the default of FlashKillWindow
+const flashKillWindow = 3 * time.Second
+
+// FlashOption configures AnalyzeFlashes
+type FlashOption func(*flashConfig)
+
+type flashConfig struct {
+	killWindow time.Duration
+}
+
+// FlashKillWindow sets how soon after a flashbang a flashed enemy has to be
+// killed for the kill to count for it, the default is 3 seconds
+func FlashKillWindow(d time.Duration) FlashOption {
+	return func(c *flashConfig) {
+		c.killWindow = d
+	}
+}
+
+// Flash is a flashbang with the players it blinded
+type Flash struct {
+	Round int `json:"round"`
+	// Player is the player that threw the flashbang
+	Player   Player `json:"player"`
+	Entindex int    `json:"entindex"`
+	// Time is the time the flashbang detonated
+	Time time.Time `json:"time"`
+	// Position is the position the throw was logged at, zero if the log
+	// only has the blinds
+	Position Position        `json:"pos"`
+	Blinds   []PlayerBlinded `json:"blinds"`
+
+	EnemiesFlashed   int `json:"enemies_flashed"`
+	TeammatesFlashed int `json:"teammates_flashed"`
+	// SelfFlashed is set if the player blinded themselves
+	SelfFlashed bool `json:"self_flashed"`
+	// EnemyBlindTime and TeamBlindTime are the blind durations summed up,
+	// TeamBlindTime includes the player themselves
+	EnemyBlindTime time.Duration `json:"enemy_blind_time"`
+	TeamBlindTime  time.Duration `json:"team_blind_time"`
+
+	// Kills are the kills of flashed enemies within the kill window
+	Kills []PlayerKill `json:"kills"`
+	// FlashAssists are the flash assists of the player for these kills
+	FlashAssists int `json:"flash_assists"`
+
+	// thrown is set once the throw is linked
+	thrown bool
+}
+
+// PlayerFlashes sums up the flashbangs of a player
+type PlayerFlashes struct {
+	Player           Player        `json:"player"`
+	Thrown           int           `json:"thrown"`
+	EnemiesFlashed   int           `json:"enemies_flashed"`
+	TeammatesFlashed int           `json:"teammates_flashed"`
+	EnemyBlindTime   time.Duration `json:"enemy_blind_time"`
+	TeamBlindTime    time.Duration `json:"team_blind_time"`
+	// Kills are kills of enemies the player flashed
+	Kills        int `json:"kills"`
+	FlashAssists int `json:"flash_assists"`
+}
+
+// RoundFlashes holds the flashbangs of a round in the order they detonated
+type RoundFlashes struct {
+	Round   int     `json:"round"`
+	Flashes []Flash `json:"flashes"`
+}
+
+// FlashReport holds the flashbangs of a match
+type FlashReport struct {
+	Rounds []RoundFlashes `json:"rounds"`
+	// Players is keyed like the Scoreboard
+	Players map[string]PlayerFlashes `json:"players"`
+}
+
+// AnalyzeFlashes links the throw of every flashbang to the players it
+// blinded by the entity index and counts the kills of flashed enemies,
+// see SplitRounds. A kill counts for the last flashbang that blinded the
+// victim.
+func AnalyzeFlashes(rounds []Round, opts ...FlashOption) FlashReport {
+	cfg := flashConfig{killWindow: flashKillWindow}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	report := FlashReport{Players: make(map[string]PlayerFlashes)}
+
+	for _, r := range rounds {
+		flashes := linkFlashes(r, cfg.killWindow)
+
+		for _, f := range flashes {
+			key := playerKey(f.Player)
+			pf := report.Players[key]
+			pf.Player = f.Player
+			pf.Thrown++
+			pf.EnemiesFlashed += f.EnemiesFlashed
+			pf.TeammatesFlashed += f.TeammatesFlashed
+			pf.EnemyBlindTime += f.EnemyBlindTime
+			pf.TeamBlindTime += f.TeamBlindTime
+			pf.Kills += len(f.Kills)
+			pf.FlashAssists += f.FlashAssists
+			report.Players[key] = pf
+		}
+
+		report.Rounds = append(report.Rounds, RoundFlashes{Round: r.Number, Flashes: flashes})
+	}
+
+	return report
+}
+
+// linkFlashes groups the throws and blinds of a round into flashbangs. Entity
+// indexes are reused, so a blind belongs to the flashbang of the same index
+// and thrower detonating at about the same time.
+func linkFlashes(r Round, killWindow time.Duration) []Flash {
+	var flashes []*Flash
+	open := make(map[int]*Flash)
+
+	flash := func(p Player, entindex int, t time.Time) *Flash {
+		f, ok := open[entindex]
+		if ok && playerKey(f.Player) == playerKey(p) && t.Sub(f.Time) <= flashLinkWindow {
+			return f
+		}
+
+		f = &Flash{Round: r.Number, Player: p, Entindex: entindex, Time: t}
+		open[entindex] = f
+		flashes = append(flashes, f)
+		return f
+	}
+
+	// blinded holds the last flashbang that blinded each player
+	blinded := make(map[string]*Flash)
+
+	for _, msg := range r.Messages {
+		switch msg := msg.(type) {
+		case PlayerThrew:
+			if msg.Grenade != "flashbang" {
+				continue
+			}
+
+			f := flash(msg.Player, msg.Entindex, msg.Time)
+			if f.thrown {
+				// a second throw is a new flashbang
+				delete(open, msg.Entindex)
+				f = flash(msg.Player, msg.Entindex, msg.Time)
+			}
+			f.Position = msg.Position
+			f.thrown = true
+
+		case PlayerBlinded:
+			f := flash(msg.Attacker, msg.Entindex, msg.Time)
+			f.Blinds = append(f.Blinds, msg)
+
+			d := time.Duration(float64(msg.For) * float64(time.Second))
+			switch {
+			case playerKey(msg.Victim) == playerKey(msg.Attacker):
+				f.SelfFlashed = true
+				f.TeamBlindTime += d
+			case msg.Victim.Side == msg.Attacker.Side:
+				f.TeammatesFlashed++
+				f.TeamBlindTime += d
+			default:
+				f.EnemiesFlashed++
+				f.EnemyBlindTime += d
+				blinded[playerKey(msg.Victim)] = f
+			}
+
+		case PlayerKill:
+			f, ok := blinded[playerKey(msg.Victim)]
+			if ok && msg.Attacker.Side == f.Player.Side && msg.Time.Sub(f.Time) <= killWindow {
+				f.Kills = append(f.Kills, msg)
+			}
+
+		case PlayerFlashAssist:
+			f, ok := blinded[playerKey(msg.Victim)]
+			if ok && playerKey(msg.Attacker) == playerKey(f.Player) && msg.Time.Sub(f.Time) <= killWindow {
+				f.FlashAssists++
+			}
+		}
+	}
+
+	linked := make([]Flash, len(flashes))
+	for i, f := range flashes {
+		linked[i] = *f
+	}
+
+	return linked
+}
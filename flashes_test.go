@@ -0,0 +1,89 @@
+package cs2log
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func threw(player, grenade string, entindex int) string {
+	return fmt.Sprintf(`%s threw %s [-716 -1636 -170] flashbang entindex %d)`, player, grenade, entindex)
+}
+
+func blinded(victim, attacker string, d float64, entindex int) string {
+	return fmt.Sprintf(`%s blinded for %.2f by %s from flashbang entindex %d`, victim, d, attacker, entindex)
+}
+
+func TestAnalyzeFlashes(t *testing.T) {
+	log := strings.Join([]string{
+		`World triggered "Match_Start" on "de_nuke"`,
+		`Starting Freeze period`,
+		`08/31/2025 - 16:30:00.000: World triggered "Round_Start"`,
+		`08/31/2025 - 16:30:05.000: ` + blinded(zont1x, magixx, 2.5, 100),
+		`08/31/2025 - 16:30:05.000: ` + blinded(kyle, magixx, 1, 100),
+		`08/31/2025 - 16:30:05.000: ` + blinded(jame, magixx, 0.5, 100),
+		`08/31/2025 - 16:30:05.000: ` + threw(magixx, "flashbang", 100),
+		`08/31/2025 - 16:30:06.000: ` + kill(jame, zont1x, "awp", false),
+		`08/31/2025 - 16:30:06.000: ` + magixx + ` flash-assisted killing ` + zont1x,
+		`08/31/2025 - 16:30:15.000: ` + threw(kyle, "flashbang", 100),
+		`08/31/2025 - 16:30:15.000: ` + blinded(kyle, kyle, 1.5, 100),
+		`08/31/2025 - 16:30:20.000: ` + kill(jame, kyle, "awp", false),
+		`08/31/2025 - 16:30:20.000: Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "0")`,
+		`08/31/2025 - 16:30:20.000: World triggered "Round_End"`,
+	}, "\n")
+
+	report := AnalyzeFlashes(SplitRounds(parseLog(t, log)))
+
+	if len(report.Rounds) != 1 || len(report.Rounds[0].Flashes) != 2 {
+		t.Fatalf("Expected a round with 2 flashbangs, got %+v", report.Rounds)
+	}
+
+	first, second := report.Rounds[0].Flashes[0], report.Rounds[0].Flashes[1]
+	if first.Player.Name != "Magixx" || len(first.Blinds) != 3 || first.Position.X != -716 {
+		t.Errorf("Expected the flashbang of Magixx with its throw and 3 blinds, got %+v", first)
+	}
+	if len(first.Kills) != 1 || first.Kills[0].Victim.Name != "Zont1x" || first.FlashAssists != 1 {
+		t.Errorf("Expected the kill of Zont1x with a flash assist, got %+v", first.Kills)
+	}
+	if second.Player.Name != "Kyle" || !second.SelfFlashed || second.EnemiesFlashed != 0 || second.TeamBlindTime != 1500*time.Millisecond {
+		t.Errorf("Expected Kyle to flash only himself, got %+v", second)
+	}
+
+	expected := PlayerFlashes{
+		Thrown:           1,
+		EnemiesFlashed:   2,
+		TeammatesFlashed: 1,
+		EnemyBlindTime:   3500 * time.Millisecond,
+		TeamBlindTime:    500 * time.Millisecond,
+		Kills:            1,
+		FlashAssists:     1,
+	}
+	magixx := report.Players["[U:1:111]"]
+	expected.Player = magixx.Player
+	if magixx != expected {
+		t.Errorf("Expected %+v, got %+v", expected, magixx)
+	}
+}
+
+func TestAnalyzeFlashes_KillWindow(t *testing.T) {
+	log := strings.Join([]string{
+		`World triggered "Match_Start" on "de_nuke"`,
+		`Starting Freeze period`,
+		`08/31/2025 - 16:30:00.000: World triggered "Round_Start"`,
+		`08/31/2025 - 16:30:05.000: ` + threw(magixx, "flashbang", 100),
+		`08/31/2025 - 16:30:05.000: ` + blinded(zont1x, magixx, 2.5, 100),
+		`08/31/2025 - 16:30:07.000: ` + kill(jame, zont1x, "awp", false),
+		`08/31/2025 - 16:30:20.000: Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "0")`,
+		`08/31/2025 - 16:30:20.000: World triggered "Round_End"`,
+	}, "\n")
+
+	rounds := SplitRounds(parseLog(t, log))
+
+	if f := AnalyzeFlashes(rounds).Rounds[0].Flashes[0]; len(f.Kills) != 1 {
+		t.Errorf("Expected the kill within 3s to count, got %+v", f.Kills)
+	}
+	if f := AnalyzeFlashes(rounds, FlashKillWindow(time.Second)).Rounds[0].Flashes[0]; len(f.Kills) != 0 {
+		t.Errorf("Expected the kill after 2s not to count within 1s, got %+v", f.Kills)
+	}
+}
@@ -0,0 +1,409 @@
+package cs2log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Checkpoint is the point up to which a log directory has been followed
+type Checkpoint struct {
+	// File is the name of the log file within the directory
+	File string `json:"file"`
+	// Offset is the byte offset of the first line not yet handled
+	Offset int64 `json:"offset"`
+	// Line is the number of lines before Offset
+	Line int `json:"line"`
+}
+
+// CheckpointStore persists the position of a Follower across restarts
+type CheckpointStore interface {
+	// Load returns the saved position, the zero Checkpoint if there is none
+	Load() (Checkpoint, error)
+	Save(Checkpoint) error
+}
+
+// CheckpointFile is a CheckpointStore keeping the position as JSON in a file
+type CheckpointFile string
+
+// Load reads the position from the file
+func (p CheckpointFile) Load() (Checkpoint, error) {
+	var pos Checkpoint
+
+	data, err := os.ReadFile(string(p))
+	if errors.Is(err, fs.ErrNotExist) {
+		return pos, nil
+	}
+	if err != nil {
+		return pos, err
+	}
+
+	err = json.Unmarshal(data, &pos)
+	return pos, err
+}
+
+// Save replaces the file atomically, so a crash never leaves it half written
+func (p CheckpointFile) Save(pos Checkpoint) error {
+	data, err := json.Marshal(pos)
+	if err != nil {
+		return err
+	}
+
+	tmp := string(p) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, string(p))
+}
+
+// Follower tails the log files a game server writes into a directory. The
+// server starts a new file on every map change, the follower switches to it
+// as soon as it is announced by a "Log file started" line or shows up when
+// polling the directory, after reading the rest of the previous file.
+//
+// Files are followed in the order of their names, which for the names the
+// game gives its logs is the order they were written in. Without a saved
+// position the follower starts at the beginning of the newest file.
+//
+// The position is saved after every batch of lines read and when Follow
+// returns. It always points at the start of a line outside of JSON blocks,
+// so a restarted follower neither skips nor repeats lines, apart from the
+// records handled after the last save if the process crashed.
+type Follower struct {
+	// Dir is the directory the log files are written to
+	Dir string
+	// Pattern selects the log files in Dir, the default is "*.log"
+	Pattern string
+	// Parser parses the lines, nil uses the patterns of ParseOrdered,
+	// which recognize the "Log file started" announcements
+	Parser *Parser
+	// Store persists the position, nil doesn't persist it
+	Store CheckpointStore
+	// PollInterval is the time between checks for new lines and files,
+	// the default is one second
+	PollInterval time.Duration
+	// Handler receives every record, tagged with the name of its file.
+	// Lines that can't be parsed are passed as records with Err set.
+	Handler func(Record)
+}
+
+// followed is the log file currently followed
+type followed struct {
+	name string
+	file *os.File
+	asm  *assembler
+	buf  []byte
+
+	// pending holds the start of a line the server hasn't finished writing
+	pending []byte
+	// offset is the end of the last complete line read
+	offset int64
+	line   int
+	// pos is the position after the last line outside of a JSON block
+	pos Checkpoint
+	// announced is the next file named by a "Log file started" line
+	announced string
+}
+
+// Follow follows the directory until ctx is cancelled or an error occurs,
+// it returns the error of the context after saving the position
+func (f *Follower) Follow(ctx context.Context) error {
+	pos := Checkpoint{}
+	if f.Store != nil {
+		var err error
+		if pos, err = f.Store.Load(); err != nil {
+			return err
+		}
+	}
+
+	var cur *followed
+	defer func() {
+		if cur != nil {
+			cur.file.Close()
+		}
+	}()
+
+	for {
+		if cur == nil {
+			start, ok, err := f.start(pos)
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				if cur, err = f.open(start); err != nil {
+					return err
+				}
+				continue
+			}
+		} else {
+			read, err := f.read(ctx, cur)
+			if err != nil {
+				return f.stop(cur, err)
+			}
+
+			if read {
+				if err := f.save(cur); err != nil {
+					return err
+				}
+				continue
+			}
+
+			next, err := f.next(cur)
+			if err != nil {
+				return f.stop(cur, err)
+			}
+
+			if next != "" {
+				// the previous file may have got its last lines since it was read
+				if _, err := f.read(ctx, cur); err != nil {
+					return f.stop(cur, err)
+				}
+				f.finish(cur)
+				cur.file.Close()
+
+				if cur, err = f.open(Checkpoint{File: next}); err != nil {
+					return err
+				}
+				if err := f.save(cur); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return f.stop(cur, ctx.Err())
+		case <-time.After(f.interval()):
+		}
+	}
+}
+
+// stop saves the position of cur and returns err
+func (f *Follower) stop(cur *followed, err error) error {
+	if cur == nil {
+		return err
+	}
+
+	if saveErr := f.save(cur); saveErr != nil && err == nil {
+		return saveErr
+	}
+
+	return err
+}
+
+func (f *Follower) save(cur *followed) error {
+	if f.Store == nil {
+		return nil
+	}
+	return f.Store.Save(cur.pos)
+}
+
+func (f *Follower) interval() time.Duration {
+	if f.PollInterval <= 0 {
+		return time.Second
+	}
+	return f.PollInterval
+}
+
+// files returns the names of the log files in the directory, sorted
+func (f *Follower) files() ([]string, error) {
+	pattern := f.Pattern
+	if pattern == "" {
+		pattern = "*.log"
+	}
+
+	paths, err := filepath.Glob(filepath.Join(f.Dir, pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(paths))
+	for i, path := range paths {
+		names[i] = filepath.Base(path)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// start returns the position to start following at, it reports
+// false if there is no file to follow yet
+func (f *Follower) start(pos Checkpoint) (Checkpoint, bool, error) {
+	files, err := f.files()
+	if err != nil || len(files) == 0 {
+		return pos, false, err
+	}
+
+	if pos.File == "" {
+		return Checkpoint{File: files[len(files)-1]}, true, nil
+	}
+
+	for _, name := range files {
+		if name == pos.File {
+			return pos, true, nil
+		}
+
+		// the saved file is gone, continue with the one after it
+		if name > pos.File {
+			return Checkpoint{File: name}, true, nil
+		}
+	}
+
+	return pos, false, nil
+}
+
+// next returns the file following cur, or "" if there is none yet
+func (f *Follower) next(cur *followed) (string, error) {
+	if cur.announced != "" {
+		_, err := os.Stat(filepath.Join(f.Dir, cur.announced))
+		if err == nil {
+			return cur.announced, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	files, err := f.files()
+	if err != nil {
+		return "", err
+	}
+
+	for _, name := range files {
+		if name > cur.name {
+			return name, nil
+		}
+	}
+
+	return "", nil
+}
+
+func (f *Follower) open(pos Checkpoint) (*followed, error) {
+	file, err := os.Open(filepath.Join(f.Dir, pos.File))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := file.Seek(pos.Offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	parse := defaultParser.ParseStateful
+	if f.Parser != nil {
+		parse = f.Parser.ParseStateful
+	}
+
+	return &followed{
+		name:   pos.File,
+		file:   file,
+		asm:    newAssembler(parse),
+		buf:    make([]byte, 32*1024),
+		offset: pos.Offset,
+		line:   pos.Line,
+		pos:    pos,
+	}, nil
+}
+
+// read handles the complete lines written to cur since it was read last,
+// it reports whether there were any
+func (f *Follower) read(ctx context.Context, cur *followed) (bool, error) {
+	read := false
+
+	for {
+		n, err := cur.file.Read(cur.buf)
+		if n > 0 {
+			cur.pending = append(cur.pending, cur.buf[:n]...)
+			if f.lines(ctx, cur) {
+				read = true
+			}
+			if ctx.Err() != nil {
+				return read, ctx.Err()
+			}
+		}
+
+		if err == io.EOF {
+			return read, nil
+		}
+		if err != nil {
+			return read, err
+		}
+	}
+}
+
+// lines handles the complete lines in cur.pending until ctx is cancelled,
+// it reports whether there were any
+func (f *Follower) lines(ctx context.Context, cur *followed) bool {
+	start := 0
+
+	for ctx.Err() == nil {
+		i := bytes.IndexByte(cur.pending[start:], '\n')
+		if i < 0 {
+			break
+		}
+
+		line := string(cur.pending[start : start+i])
+		start += i + 1
+		cur.offset += int64(i + 1)
+		f.feed(cur, strings.TrimSuffix(line, "\r"))
+	}
+
+	n := copy(cur.pending, cur.pending[start:])
+	cur.pending = cur.pending[:n]
+
+	return start > 0
+}
+
+// feed parses a line of cur and passes the completed record to the Handler
+func (f *Follower) feed(cur *followed, line string) {
+	cur.line++
+	rec, ok := cur.asm.feed(line, cur.line)
+
+	if !cur.asm.state.InJSONBlock {
+		cur.pos = Checkpoint{File: cur.name, Offset: cur.offset, Line: cur.line}
+	}
+
+	if !ok {
+		return
+	}
+
+	if lf, isLogFile := rec.Message.(LogFile); isLogFile && lf.Action == "started" {
+		if name := filepath.Base(lf.Filename); name != cur.name {
+			cur.announced = name
+		}
+	}
+
+	rec.File = cur.name
+	if f.Handler != nil {
+		f.Handler(rec)
+	}
+}
+
+// finish handles the rest of a file that won't be written to anymore
+func (f *Follower) finish(cur *followed) {
+	// the last line of a file may lack the line break
+	if len(cur.pending) > 0 {
+		line := string(cur.pending)
+		cur.offset += int64(len(cur.pending))
+		cur.pending = cur.pending[:0]
+		f.feed(cur, strings.TrimSuffix(line, "\r"))
+	}
+
+	if rec, ok := cur.asm.flush(); ok {
+		rec.File = cur.name
+		if f.Handler != nil {
+			f.Handler(rec)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package cs2log
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	followFileA = "L000_000_000_000_0_202508191512_000.log"
+	followFileB = "L000_000_000_000_0_202508191545_000.log"
+)
+
+func appendFile(t *testing.T, path, text string) {
+	t.Helper()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// startFollower runs the follower until the returned function is called
+func startFollower(t *testing.T, f *Follower) (<-chan Record, func()) {
+	t.Helper()
+
+	records := make(chan Record, 100)
+	f.Handler = func(r Record) { records <- r }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- f.Follow(ctx) }()
+
+	return records, func() {
+		cancel()
+		if err := <-done; err != context.Canceled {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	}
+}
+
+// expectRecords checks the next records as "file:line:type"
+func expectRecords(t *testing.T, records <-chan Record, expected ...string) {
+	t.Helper()
+
+	for _, e := range expected {
+		select {
+		case r := <-records:
+			got := fmt.Sprintf("%s:%d:<error>", r.File, r.Line)
+			if r.Err == nil {
+				got = fmt.Sprintf("%s:%d:%s", r.File, r.Line, r.Message.GetType())
+			}
+			if got != e {
+				t.Fatalf("Expected %s, got %s", e, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected %s, got nothing", e)
+		}
+	}
+
+	select {
+	case r := <-records:
+		t.Fatalf("Unexpected record %+v", r)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestFollower(t *testing.T) {
+	dir := t.TempDir()
+	store := CheckpointFile(filepath.Join(t.TempDir(), "checkpoint.json"))
+	a, b := filepath.Join(dir, followFileA), filepath.Join(dir, followFileB)
+
+	newFollower := func() *Follower {
+		return &Follower{Dir: dir, Store: store, PollInterval: 5 * time.Millisecond}
+	}
+
+	appendFile(t, a, "08/31/2025 - 16:30:17.000: World triggered \"Round_Start\"\n"+
+		"08/31/2025 - 16:30:18.000: JSON_BEGIN{\n"+
+		"08/31/2025 - 16:30:18.000: \"name\": \"round_stats\",\n")
+
+	records, stop := startFollower(t, newFollower())
+	expectRecords(t, records, followFileA+":1:WorldRoundStart")
+	stop()
+
+	// the checkpoint stays before the unfinished JSON block
+	cp, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.File != followFileA || cp.Line != 1 {
+		t.Fatalf("Expected checkpoint after line 1 of %s, got %+v", followFileA, cp)
+	}
+
+	appendFile(t, a, "08/31/2025 - 16:30:18.000: \"round_number\" : \"2\",\n"+
+		"08/31/2025 - 16:30:18.000: \"players\" : {\n"+
+		"08/31/2025 - 16:30:18.000: }}JSON_END\n"+
+		"08/31/2025 - 16:30:19.000: World triggered \"Round_")
+
+	records, stop = startFollower(t, newFollower())
+	defer stop()
+	expectRecords(t, records, followFileA+":2:JSONStatistics")
+
+	// the rest of a partly written line
+	appendFile(t, a, "End\"\n")
+	expectRecords(t, records, followFileA+":7:WorldRoundEnd")
+
+	// the last line of the rotated file has no line break
+	appendFile(t, a, "08/31/2025 - 16:30:20.000: Log file closed")
+	appendFile(t, b, "08/31/2025 - 16:30:21.000: Log file started (file \"logs/"+followFileB+"\") (game \"/home/cs2/game/csgo\") (version \"10526\")\n"+
+		"08/31/2025 - 16:30:22.000: World triggered \"Round_Start\"\n")
+
+	expectRecords(t, records,
+		followFileA+":8:LogFile",
+		followFileB+":1:LogFile",
+		followFileB+":2:WorldRoundStart",
+	)
+}
+
+func TestFollower_Announced(t *testing.T) {
+	dir := t.TempDir()
+	other := "L000_000_000_000_0_202508191600_000.log"
+
+	appendFile(t, filepath.Join(dir, followFileA), "08/31/2025 - 16:30:17.000: World triggered \"Round_Start\"\n"+
+		"08/31/2025 - 16:30:18.000: Log file started (file \"logs/"+other+"\")\n")
+	appendFile(t, filepath.Join(dir, followFileB), "08/31/2025 - 16:30:19.000: Match paused\n")
+	appendFile(t, filepath.Join(dir, other), "08/31/2025 - 16:30:19.000: Match unpaused\n")
+
+	store := CheckpointFile(filepath.Join(t.TempDir(), "checkpoint.json"))
+	if err := store.Save(Checkpoint{File: followFileA}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, stop := startFollower(t, &Follower{Dir: dir, Store: store, PollInterval: 5 * time.Millisecond})
+	defer stop()
+
+	// the announced file is followed even though another one sorts before it
+	expectRecords(t, records,
+		followFileA+":1:WorldRoundStart",
+		followFileA+":2:LogFile",
+		other+":1:MatchPause",
+	)
+}
+
+func TestCheckpointFile(t *testing.T) {
+	store := CheckpointFile(filepath.Join(t.TempDir(), "checkpoint.json"))
+
+	cp, err := store.Load()
+	if err != nil || cp != (Checkpoint{}) {
+		t.Fatalf("Expected empty checkpoint, got %+v, %v", cp, err)
+	}
+
+	saved := Checkpoint{File: followFileA, Offset: 1234, Line: 42}
+	if err := store.Save(saved); err != nil {
+		t.Fatal(err)
+	}
+
+	if cp, err = store.Load(); err != nil || cp != saved {
+		t.Errorf("Expected %+v, got %+v, %v", saved, cp, err)
+	}
+}
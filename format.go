@@ -0,0 +1,286 @@
+package cs2log
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Format renders a message as a log line with the "L MM/DD/YYYY - HH:MM:SS.mmm: "
+// prefix, the inverse of ParseOrdered. JSONStatistics are rendered as a block
+// of lines separated by "\n" for ParseLines, the parser rebuilds RawJSON.
+// The time is written in its own location with milliseconds, floats with the
+// precision of the log. Messages the parser never produces are rendered but
+// don't parse back into the same message: MatchStatusTeam parses as TeamPlaying,
+// the start of a FreezePeriod as FreezTimeStart and a TriggeredEvent of an event
+// with its own pattern as that message. Types Format doesn't know render as "".
+func Format(m Message) string {
+	content, ok := formatContent(m)
+	if !ok {
+		return ""
+	}
+
+	prefix := "L " + m.GetTime().Format("01/02/2006 - 15:04:05.000") + ": "
+	lines := strings.Split(content, "\n")
+	for i, l := range lines {
+		lines[i] = prefix + l
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatContent renders a message without the timestamp
+func formatContent(m Message) (string, bool) {
+	switch m := m.(type) {
+	case ServerMessage:
+		return fmt.Sprintf(`server_message: "%s"`, m.Text), true
+	case FreezTimeStart:
+		return `Starting Freeze period`, true
+	case WorldMatchStart:
+		return fmt.Sprintf(`World triggered "Match_Start" on "%s"`, m.Map), true
+	case WorldRoundStart:
+		return `World triggered "Round_Start"`, true
+	case WorldRoundRestart:
+		return fmt.Sprintf(`World triggered "Restart_Round_(%d_second)"`, m.Timeleft), true
+	case WorldRoundEnd:
+		return `World triggered "Round_End"`, true
+	case WorldGameCommencing:
+		return `World triggered "Game_Commencing"`, true
+	case TeamScored:
+		return fmt.Sprintf(`Team "%s" scored "%d" with "%d" players`, m.Side, m.Score, m.NumPlayers), true
+	case TeamNotice:
+		return fmt.Sprintf(`Team "%s" triggered "%s" (CT "%d") (T "%d")`, m.Side, m.Notice, m.ScoreCT, m.ScoreT), true
+	case PlayerConnected:
+		return fmt.Sprintf(`%s connected, address "%s"`, formatPlayer(m.Player), m.Address), true
+	case PlayerDisconnected:
+		return fmt.Sprintf(`%s disconnected (reason "%s")`, formatPlayer(m.Player), m.Reason), true
+	case PlayerEntered:
+		return fmt.Sprintf(`%s entered the game`, formatPlayer(m.Player)), true
+	case PlayerBanned:
+		return fmt.Sprintf(`Banid: %s was banned "%s" by "%s"`, formatPlayer(m.Player), m.Duration, m.By), true
+	case PlayerSwitched:
+		return fmt.Sprintf(`"%s<%d><%s>" switched from team <%s> to <%s>`, m.Player.Name, m.Player.ID, m.Player.SteamID, m.From, m.To), true
+	case PlayerSay:
+		say := "say"
+		if m.Team {
+			say = "say_team"
+		}
+		return fmt.Sprintf(`%s %s "%s"`, formatPlayer(m.Player), say, m.Text), true
+	case PlayerPurchase:
+		return fmt.Sprintf(`%s purchased "%s"`, formatPlayer(m.Player), m.Item), true
+	case PlayerKill:
+		var flags []string
+		if m.Headshot {
+			flags = append(flags, "headshot")
+		}
+		if m.Penetrated {
+			flags = append(flags, "penetrated")
+		}
+		line := fmt.Sprintf(`%s %s killed %s %s with "%s"`,
+			formatPlayer(m.Attacker), formatPosition(m.AttackerPosition),
+			formatPlayer(m.Victim), formatPosition(m.VictimPosition), m.Weapon)
+		if len(flags) > 0 {
+			line += " (" + strings.Join(flags, " ") + ")"
+		}
+		return line, true
+	case PlayerKillAssist:
+		return fmt.Sprintf(`%s assisted killing %s`, formatPlayer(m.Attacker), formatPlayer(m.Victim)), true
+	case PlayerFlashAssist:
+		return fmt.Sprintf(`%s flash-assisted killing %s`, formatPlayer(m.Attacker), formatPlayer(m.Victim)), true
+	case PlayerAttack:
+		return fmt.Sprintf(`%s %s attacked %s %s with "%s" (damage "%d") (damage_armor "%d") (health "%d") (armor "%d") (hitgroup "%s")`,
+			formatPlayer(m.Attacker), formatPosition(m.AttackerPosition),
+			formatPlayer(m.Victim), formatPosition(m.VictimPosition),
+			m.Weapon, m.Damage, m.DamageArmor, m.Health, m.Armor, m.Hitgroup), true
+	case PlayerKilledBomb:
+		return fmt.Sprintf(`%s %s was killed by the bomb.`, formatPlayer(m.Player), formatPosition(m.Position)), true
+	case PlayerKilledSuicide:
+		return fmt.Sprintf(`%s %s committed suicide with "%s"`, formatPlayer(m.Player), formatPosition(m.Position), m.With), true
+	case PlayerPickedUp:
+		return fmt.Sprintf(`%s picked up "%s"`, formatPlayer(m.Player), m.Item), true
+	case PlayerDropped:
+		return fmt.Sprintf(`%s dropped "%s"`, formatPlayer(m.Player), m.Item), true
+	case PlayerMoneyChange:
+		sign, b := "+", m.Equation.B
+		if b < 0 {
+			sign, b = "-", -b
+		}
+		line := fmt.Sprintf(`%s money change %d%s%d = $%d`, formatPlayer(m.Player), m.Equation.A, sign, b, m.Equation.Result)
+		if m.Purchase != "" {
+			line += " (tracked) (purchase: " + m.Purchase + ")"
+		}
+		return line, true
+	case PlayerBombGot:
+		return formatPlayer(m.Player) + ` triggered "Got_The_Bomb"`, true
+	case PlayerBombPlanted:
+		return formatPlayer(m.Player) + ` triggered "Planted_The_Bomb"`, true
+	case PlayerBombDropped:
+		return formatPlayer(m.Player) + ` triggered "Dropped_The_Bomb"`, true
+	case PlayerBombBeginDefuse:
+		if m.Kit {
+			return formatPlayer(m.Player) + ` triggered "Begin_Bomb_Defuse_With_Kit"`, true
+		}
+		return formatPlayer(m.Player) + ` triggered "Begin_Bomb_Defuse_Without_Kit"`, true
+	case PlayerBombDefused:
+		return formatPlayer(m.Player) + ` triggered "Defused_The_Bomb"`, true
+	case PlayerThrew:
+		line := fmt.Sprintf(`%s threw %s %s`, formatPlayer(m.Player), m.Grenade, formatPosition(m.Position))
+		if m.Entindex != 0 {
+			line += fmt.Sprintf(" flashbang entindex %d)", m.Entindex)
+		}
+		return line, true
+	case PlayerBlinded:
+		return fmt.Sprintf(`%s blinded for %.2f by %s from flashbang entindex %d`,
+			formatPlayer(m.Victim), m.For, formatPlayer(m.Attacker), m.Entindex), true
+	case ProjectileSpawned:
+		return fmt.Sprintf(`Molotov projectile spawned at %f %f %f, velocity %f %f %f`,
+			m.Position.X, m.Position.Y, m.Position.Z, m.Velocity.X, m.Velocity.Y, m.Velocity.Z), true
+	case GameOver:
+		return fmt.Sprintf(`Game Over: %s %s %s score %d:%d after %d min`, m.Mode, m.MapGroup, m.Map, m.ScoreCT, m.ScoreT, m.Duration), true
+	case Unknown:
+		return m.Raw, true
+
+	case PlayerLeftBuyzone:
+		return fmt.Sprintf(`%s left buyzone with [ %s ]`, formatPlayer(m.Player), strings.Join(m.Equipment, " ")), true
+	case PlayerValidated:
+		return formatPlayer(m.Player) + ` STEAM USERID validated`, true
+	case PlayerNameChanged:
+		return fmt.Sprintf(`%s changed name to "%s"`, formatPlayer(m.Player), m.Name), true
+	case PlayerAccolade:
+		kind := "ROUND"
+		if m.IsFinal {
+			kind = "FINAL"
+		}
+		return fmt.Sprintf(`ACCOLADE, %s: {%s}, %s<%d>, VALUE: %f`, kind, m.Type, m.Player.Name, m.Player.ID, m.Value), true
+	case MatchStatus:
+		return fmt.Sprintf(`MatchStatus: Score: %d:%d on map "%s" RoundsPlayed: %d`, m.ScoreCT, m.ScoreT, m.Map, m.RoundsPlayed), true
+	case TeamPlaying:
+		return fmt.Sprintf(`Team playing "%s": %s`, m.Side, m.TeamName), true
+	case MatchStatusTeam:
+		return fmt.Sprintf(`MatchStatus: Team playing "%s": %s`, m.Side, m.TeamName), true
+	case MatchPause:
+		switch m.Action {
+		case "enabled":
+			return `Match pause is enabled`, true
+		case "disabled":
+			return `Match pause is disabled`, true
+		}
+		return `Match unpaused`, true
+	case GrenadeThrowDebug:
+		return fmt.Sprintf(`"%s" sv_throw_%s %s %s %s %s %s %s`, m.Player.Name, m.GrenadeType,
+			formatFloat(m.Position.X), formatFloat(m.Position.Y), formatFloat(m.Position.Z),
+			formatFloat(m.Velocity.X), formatFloat(m.Velocity.Y), formatFloat(m.Velocity.Z)), true
+	case ServerCvar:
+		return fmt.Sprintf(`server_cvar: "%s" "%s"`, m.Name, m.Value), true
+	case RconCommand:
+		return fmt.Sprintf(`rcon from "%s": command "%s"`, m.Source, m.Command), true
+	case LoadingMap:
+		return fmt.Sprintf(`Loading map "%s"`, m.Map), true
+	case StartedMap:
+		return fmt.Sprintf(`Started map "%s"`, m.Map), true
+	case LogFile:
+		if m.Action == "closed" {
+			return `Log file closed`, true
+		}
+		return fmt.Sprintf(`Log file started (file "%s")`, m.Filename), true
+	case TriggeredEvent:
+		return fmt.Sprintf(`World triggered "%s"`, m.Event), true
+	case ChatCommand:
+		text := "." + m.Command
+		if m.Args != "" {
+			text += " " + m.Args
+		}
+		return fmt.Sprintf(`%s say "%s"`, formatPlayer(m.Player), text), true
+	case GameOverDetailed:
+		return fmt.Sprintf(`Game Over: %s %s score %d:%d after %d min`, m.Mode, m.Map, m.ScoreCT, m.ScoreT, m.Duration), true
+	case BombEvent:
+		return formatPlayer(m.Player) + ` triggered "Bomb_Begin_Plant"`, true
+	case FreezePeriod:
+		if m.Action == "end" {
+			return `World triggered "Round_Freeze_End"`, true
+		}
+		return `Starting Freeze period`, true
+	case WarmupStart:
+		return `World triggered "Warmup_Start"`, true
+	case WarmupEnd:
+		return `World triggered "Warmup_End"`, true
+	case JSONStatistics:
+		return formatStatistics(m), true
+	}
+
+	return "", false
+}
+
+// formatPlayer renders a player as "Name<ID><SteamID><Side>"
+func formatPlayer(p Player) string {
+	return fmt.Sprintf(`"%s<%d><%s><%s>"`, p.Name, p.ID, p.SteamID, p.Side)
+}
+
+// formatPosition renders a position as "[X Y Z]"
+func formatPosition(p Position) string {
+	return fmt.Sprintf("[%d %d %d]", p.X, p.Y, p.Z)
+}
+
+// formatFloat renders a float with as many digits as needed to parse back
+func formatFloat(f float32) string {
+	return strconv.FormatFloat(float64(f), 'f', -1, 32)
+}
+
+// statisticsFields are the player statistics in the order of the log
+func statisticsFields(s PlayerStatistics) []string {
+	ints := func(v ...int) []string {
+		f := make([]string, len(v))
+		for i, n := range v {
+			f[i] = strconv.Itoa(n)
+		}
+		return f
+	}
+
+	fields := ints(s.AccountID, s.Team, s.Money, s.Kills, s.Deaths, s.Assists, s.Damage)
+	fields = append(fields, strconv.FormatFloat(s.HeadshotPct, 'f', 2, 64), strconv.FormatFloat(s.KDR, 'f', 2, 64))
+	return append(fields, ints(s.ADR, s.MVP, s.EnemiesFlashed, s.UtilityDamage, s.TripleKills,
+		s.QuadKills, s.AceKills, s.ClutchKills, s.FirstKills, s.PistolKills, s.SniperKills,
+		s.BlindKills, s.BombKills, s.FireDamage, s.UniqueKills, s.Dinks, s.ChickenKills)...)
+}
+
+// formatStatistics renders a JSON statistics block, players sorted by key.
+// Statistics without players parse back with an empty map.
+func formatStatistics(s JSONStatistics) string {
+	lines := []string{
+		"JSON_BEGIN{",
+		`"name": ` + jsonString(s.Name) + ",",
+		`"round_number" : "` + strconv.Itoa(s.RoundNumber) + `",`,
+		`"score_t" : "` + strconv.Itoa(s.ScoreT) + `",`,
+		`"score_ct" : "` + strconv.Itoa(s.ScoreCT) + `",`,
+		`"map" : ` + jsonString(s.Map) + ",",
+		`"server" : ` + jsonString(s.Server) + ",",
+	}
+
+	if s.Fields != nil {
+		lines = append(lines, `"fields" : `+jsonString(strings.Join(s.Fields, ",")))
+	}
+
+	// the block always ends with the players, "}}JSON_END" closes them
+	keys := make([]string, 0, len(s.Players))
+	for k := range s.Players {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines = append(lines, `"players" : {`)
+	for _, k := range keys {
+		values := strings.Join(statisticsFields(s.Players[k]), ",")
+		lines = append(lines, jsonString(k)+" : "+jsonString(values))
+	}
+
+	return strings.Join(append(lines, "}}JSON_END"), "\n")
+}
+
+// jsonString quotes a string for JSON without escaping html
+func jsonString(s string) string {
+	buf := &bytes.Buffer{}
+	newJSONEncoder(buf).Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,343 @@
+package cs2log
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// generator creates random messages within what the log format can express
+type generator struct {
+	*rand.Rand
+}
+
+func (g generator) pick(s ...string) string {
+	return s[g.Intn(len(s))]
+}
+
+func (g generator) word() string {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_"
+	b := make([]byte, 1+g.Intn(10))
+	for i := range b {
+		b[i] = letters[g.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+func (g generator) text() string {
+	words := make([]string, 1+g.Intn(4))
+	for i := range words {
+		words[i] = g.word()
+	}
+	return strings.Join(words, g.pick(" ", " - ", ", ", "|"))
+}
+
+func (g generator) time() time.Time {
+	return time.Date(2025, time.Month(1+g.Intn(12)), 1+g.Intn(28), g.Intn(24), g.Intn(60), g.Intn(60), g.Intn(1000)*int(time.Millisecond), time.UTC)
+}
+
+func (g generator) meta(ty string) Meta {
+	return NewMeta(g.time(), ty)
+}
+
+func (g generator) steamID() string {
+	switch g.Intn(3) {
+	case 0:
+		return botSteamID
+	case 1:
+		return fmt.Sprintf("STEAM_1:%d:%d", g.Intn(2), g.Intn(1<<30))
+	}
+	return fmt.Sprintf("[U:1:%d]", 1+g.Intn(1<<31-1))
+}
+
+func (g generator) player(sides ...string) Player {
+	return Player{Name: g.text(), ID: g.Intn(100), SteamID: g.steamID(), Side: g.pick(sides...)}
+}
+
+func (g generator) position() Position {
+	return Position{X: g.Intn(8000) - 4000, Y: g.Intn(8000) - 4000, Z: g.Intn(2000) - 1000}
+}
+
+// float returns a float with three decimals, as precise as the log writes them
+func (g generator) float() float32 {
+	return float32(g.Intn(8000000)-4000000) / 1000
+}
+
+// hundredths returns a float with two decimals
+func (g generator) hundredths() float64 {
+	return float64(g.Intn(100000)) / 100
+}
+
+func (g generator) statistics() JSONStatistics {
+	s := JSONStatistics{
+		Meta:        g.meta("JSONStatistics"),
+		Name:        "round_stats",
+		RoundNumber: g.Intn(30),
+		ScoreT:      g.Intn(16),
+		ScoreCT:     g.Intn(16),
+		Map:         g.word(),
+		Server:      g.text(),
+		Fields:      strings.Split("accountid,team,money,kills,deaths,assists,dmg,hsp,kdr,adr,mvp,ef,ud,3k,4k,5k,clutchk,firstk,pistolk,sniperk,blindk,bombk,firedmg,uniquek,dinks,chickenk", ","),
+		Players:     make(map[string]PlayerStatistics),
+	}
+
+	for i := g.Intn(10); i >= 0; i-- {
+		s.Players[fmt.Sprintf("player_%d", i)] = PlayerStatistics{
+			AccountID: g.Intn(1 << 30), Team: 2 + g.Intn(2), Money: g.Intn(16000),
+			Kills: g.Intn(30), Deaths: g.Intn(30), Assists: g.Intn(10), Damage: g.Intn(3000),
+			HeadshotPct: g.hundredths(), KDR: g.hundredths(), ADR: g.Intn(200), MVP: g.Intn(10),
+			EnemiesFlashed: g.Intn(20), UtilityDamage: g.Intn(500), TripleKills: g.Intn(3),
+			QuadKills: g.Intn(2), AceKills: g.Intn(2), ClutchKills: g.Intn(5), FirstKills: g.Intn(5),
+			PistolKills: g.Intn(5), SniperKills: g.Intn(5), BlindKills: g.Intn(5), BombKills: g.Intn(2),
+			FireDamage: g.Intn(100), UniqueKills: g.Intn(20), Dinks: g.Intn(10), ChickenKills: g.Intn(2),
+		}
+	}
+
+	return s
+}
+
+// messageGenerators creates every message type Format renders and the parser
+// produces, MatchStatusTeam and the start of FreezePeriod are left out
+var messageGenerators = map[string]func(g generator) Message{
+	"ServerMessage":       func(g generator) Message { return ServerMessage{g.meta("ServerMessage"), g.word()} },
+	"FreezTimeStart":      func(g generator) Message { return FreezTimeStart{g.meta("FreezTimeStart")} },
+	"WorldMatchStart":     func(g generator) Message { return WorldMatchStart{g.meta("WorldMatchStart"), g.word()} },
+	"WorldRoundStart":     func(g generator) Message { return WorldRoundStart{g.meta("WorldRoundStart")} },
+	"WorldRoundRestart":   func(g generator) Message { return WorldRoundRestart{g.meta("WorldRoundRestart"), g.Intn(10)} },
+	"WorldRoundEnd":       func(g generator) Message { return WorldRoundEnd{g.meta("WorldRoundEnd")} },
+	"WorldGameCommencing": func(g generator) Message { return WorldGameCommencing{g.meta("WorldGameCommencing")} },
+	"TeamScored": func(g generator) Message {
+		return TeamScored{g.meta("TeamScored"), g.pick("CT", "TERRORIST"), g.Intn(16), g.Intn(6)}
+	},
+	"TeamNotice": func(g generator) Message {
+		return TeamNotice{g.meta("TeamNotice"), g.pick("CT", "TERRORIST"), g.pick("SFUI_Notice_Target_Bombed", "SFUI_Notice_CTs_Win"), g.Intn(16), g.Intn(16)}
+	},
+	"PlayerConnected": func(g generator) Message {
+		return PlayerConnected{g.meta("PlayerConnected"), g.player(""), g.pick("", "10.0.0.1:27005")}
+	},
+	"PlayerDisconnected": func(g generator) Message {
+		return PlayerDisconnected{g.meta("PlayerDisconnected"), g.player("CT", "TERRORIST", "Unassigned", ""), g.text()}
+	},
+	"PlayerEntered": func(g generator) Message { return PlayerEntered{g.meta("PlayerEntered"), g.player("")} },
+	"PlayerBanned": func(g generator) Message {
+		return PlayerBanned{g.meta("PlayerBanned"), g.player(""), g.pick("permanently", "for 15.00 minutes"), g.word()}
+	},
+	"PlayerSwitched": func(g generator) Message {
+		sides := []string{"Unassigned", "Spectator", "TERRORIST", "CT"}
+		return PlayerSwitched{g.meta("PlayerSwitched"), g.player(""), g.pick(sides...), g.pick(sides...)}
+	},
+	"PlayerSay": func(g generator) Message {
+		return PlayerSay{g.meta("PlayerSay"), g.player("CT", "TERRORIST"), g.text(), g.Intn(2) == 0}
+	},
+	"PlayerPurchase": func(g generator) Message {
+		return PlayerPurchase{g.meta("PlayerPurchase"), g.player("CT", "TERRORIST"), g.word()}
+	},
+	"PlayerKill": func(g generator) Message {
+		return PlayerKill{g.meta("PlayerKill"), g.player("CT", "TERRORIST"), g.position(), g.player("CT", "TERRORIST"), g.position(), g.word(), g.Intn(2) == 0, g.Intn(2) == 0}
+	},
+	"PlayerKillAssist": func(g generator) Message {
+		return PlayerKillAssist{g.meta("PlayerKillAssist"), g.player("CT", "TERRORIST"), g.player("CT", "TERRORIST")}
+	},
+	"PlayerFlashAssist": func(g generator) Message {
+		return PlayerFlashAssist{g.meta("PlayerFlashAssist"), g.player("CT", "TERRORIST"), g.player("CT", "TERRORIST")}
+	},
+	"PlayerAttack": func(g generator) Message {
+		return PlayerAttack{g.meta("PlayerAttack"), g.player("CT", "TERRORIST"), g.position(), g.player("CT", "TERRORIST"), g.position(),
+			g.word(), g.Intn(100), g.Intn(100), g.Intn(100), g.Intn(100), g.pick("head", "chest", "left leg", "generic")}
+	},
+	"PlayerKilledBomb": func(g generator) Message {
+		return PlayerKilledBomb{g.meta("PlayerKilledBomb"), g.player("CT", "TERRORIST"), g.position()}
+	},
+	"PlayerKilledSuicide": func(g generator) Message {
+		return PlayerKilledSuicide{g.meta("PlayerKilledSuicide"), g.player("CT", "TERRORIST"), g.position(), g.word()}
+	},
+	"PlayerPickedUp": func(g generator) Message {
+		return PlayerPickedUp{g.meta("PlayerPickedUp"), g.player("CT", "TERRORIST"), g.word()}
+	},
+	"PlayerDropped": func(g generator) Message {
+		return PlayerDropped{g.meta("PlayerDropped"), g.player("CT", "TERRORIST", "Unassigned"), g.word()}
+	},
+	"PlayerMoneyChange": func(g generator) Message {
+		a := g.Intn(16000)
+		b := g.Intn(6000) - a
+		return PlayerMoneyChange{g.meta("PlayerMoneyChange"), g.player("CT", "TERRORIST"), Equation{a, b, a + b}, g.pick("", g.word())}
+	},
+	"PlayerBombGot": func(g generator) Message { return PlayerBombGot{g.meta("PlayerBombGot"), g.player("TERRORIST")} },
+	"PlayerBombPlanted": func(g generator) Message {
+		return PlayerBombPlanted{g.meta("PlayerBombPlanted"), g.player("TERRORIST")}
+	},
+	"PlayerBombDropped": func(g generator) Message {
+		return PlayerBombDropped{g.meta("PlayerBombDropped"), g.player("TERRORIST")}
+	},
+	"PlayerBombBeginDefuse": func(g generator) Message {
+		return PlayerBombBeginDefuse{g.meta("PlayerBombBeginDefuse"), g.player("CT"), g.Intn(2) == 0}
+	},
+	"PlayerBombDefused": func(g generator) Message { return PlayerBombDefused{g.meta("PlayerBombDefused"), g.player("CT")} },
+	"PlayerThrew": func(g generator) Message {
+		return PlayerThrew{g.meta("PlayerThrew"), g.player("CT", "TERRORIST"), g.position(), g.Intn(500), g.pick("flashbang", "hegrenade", "smokegrenade")}
+	},
+	"PlayerBlinded": func(g generator) Message {
+		return PlayerBlinded{g.meta("PlayerBlinded"), g.player("CT", "TERRORIST"), g.player("CT", "TERRORIST", ""), float32(g.Intn(1000)) / 100, g.Intn(500)}
+	},
+	"ProjectileSpawned": func(g generator) Message {
+		return ProjectileSpawned{g.meta("ProjectileSpawned"), PositionFloat{g.float(), g.float(), g.float()}, Velocity{g.float(), g.float(), g.float()}}
+	},
+	"GameOver": func(g generator) Message {
+		return GameOver{g.meta("GameOver"), "competitive", g.word(), g.word(), g.Intn(17), g.Intn(17), g.Intn(90)}
+	},
+	"Unknown": func(g generator) Message {
+		return Unknown{g.meta("Unknown"), "unknown " + g.text()}
+	},
+
+	"PlayerLeftBuyzone": func(g generator) Message {
+		equipment := make([]string, 1+g.Intn(5))
+		for i := range equipment {
+			equipment[i] = g.pick("weapon_knife", "weapon_ak47", "kevlar(100)", "helmet", g.word())
+		}
+		return PlayerLeftBuyzone{g.meta("PlayerLeftBuyzone"), g.player("CT", "TERRORIST"), equipment}
+	},
+	"PlayerValidated": func(g generator) Message { return PlayerValidated{g.meta("PlayerValidated"), g.player("")} },
+	"PlayerNameChanged": func(g generator) Message {
+		return PlayerNameChanged{g.meta("PlayerNameChanged"), g.player("CT", "TERRORIST", ""), g.text()}
+	},
+	"PlayerAccolade": func(g generator) Message {
+		return PlayerAccolade{g.meta("PlayerAccolade"), g.pick("3k", "mvp", "burndamage"), Player{Name: g.text(), ID: g.Intn(100)}, g.hundredths(), g.Intn(2) == 0}
+	},
+	"MatchStatus": func(g generator) Message {
+		return MatchStatus{g.meta("MatchStatus"), g.Intn(16), g.Intn(16), g.word(), g.Intn(30) - 1}
+	},
+	"TeamPlaying": func(g generator) Message {
+		return TeamPlaying{g.meta("TeamPlaying"), g.pick("CT", "TERRORIST"), g.text()}
+	},
+	"MatchPause": func(g generator) Message {
+		return MatchPause{Meta: g.meta("MatchPause"), Action: g.pick("enabled", "disabled", "unpaused")}
+	},
+	"GrenadeThrowDebug": func(g generator) Message {
+		m := GrenadeThrowDebug{
+			Meta:        g.meta("GrenadeThrowDebug"),
+			Player:      Player{Name: g.text()},
+			GrenadeType: g.pick("molotov", "smokegrenade", "flashgrenade", "hegrenade"),
+			Position:    PositionFloat{g.float(), g.float(), g.float()},
+			Velocity:    Velocity{g.float(), g.float(), g.float()},
+		}
+		m.DebugCommand = strings.Join([]string{"sv_throw_" + m.GrenadeType,
+			formatFloat(m.Position.X), formatFloat(m.Position.Y), formatFloat(m.Position.Z),
+			formatFloat(m.Velocity.X), formatFloat(m.Velocity.Y), formatFloat(m.Velocity.Z)}, " ")
+		return m
+	},
+	"ServerCvar": func(g generator) Message {
+		return ServerCvar{g.meta("ServerCvar"), g.pick("mp_freezetime", "sv_cheats", g.word()), g.word()}
+	},
+	"RconCommand": func(g generator) Message {
+		return RconCommand{g.meta("RconCommand"), "192.168.1.100:12345", g.text()}
+	},
+	"LoadingMap": func(g generator) Message { return LoadingMap{g.meta("LoadingMap"), g.word()} },
+	"StartedMap": func(g generator) Message { return StartedMap{g.meta("StartedMap"), g.word()} },
+	"LogFile": func(g generator) Message {
+		if g.Intn(2) == 0 {
+			return LogFile{Meta: g.meta("LogFile"), Action: "closed"}
+		}
+		return LogFile{g.meta("LogFile"), "started", "logs/" + g.word() + ".log"}
+	},
+	"TriggeredEvent": func(g generator) Message {
+		return TriggeredEvent{g.meta("TriggeredEvent"), "Custom_" + g.word(), map[string]string{}}
+	},
+	"ChatCommand": func(g generator) Message {
+		command, args := g.word(), g.pick("", g.text())
+		return ChatCommand{g.meta("ChatCommand"), g.player("CT", "TERRORIST"), command, args, "." + command + " " + args}
+	},
+	"GameOverDetailed": func(g generator) Message {
+		return GameOverDetailed{g.meta("GameOverDetailed"), "competitive", g.word(), g.Intn(17), g.Intn(17), g.Intn(90)}
+	},
+	"BombEvent": func(g generator) Message {
+		return BombEvent{Meta: g.meta("BombEvent"), Player: g.player("TERRORIST"), Action: "begin_plant"}
+	},
+	"FreezePeriod": func(g generator) Message { return FreezePeriod{g.meta("FreezePeriod"), "end"} },
+	"WarmupStart":  func(g generator) Message { return WarmupStart{g.meta("WarmupStart")} },
+	"WarmupEnd":    func(g generator) Message { return WarmupEnd{g.meta("WarmupEnd")} },
+	"JSONStatistics": func(g generator) Message {
+		return g.statistics()
+	},
+}
+
+func TestFormat_RoundTrip(t *testing.T) {
+	g := generator{rand.New(rand.NewSource(1))}
+
+	for name, generate := range messageGenerators {
+		for i := 0; i < 100; i++ {
+			m := generate(g)
+			if m.GetType() != name {
+				t.Fatalf("%s: generated %s", name, m.GetType())
+			}
+
+			line := Format(m)
+			messages, errs := ParseLinesOrdered(strings.Split(line, "\n"))
+			if len(errs) > 0 || len(messages) != 1 {
+				t.Fatalf("%s: %q parsed to %v, %v", name, line, messages, errs)
+			}
+
+			parsed := messages[0]
+			if s, ok := parsed.(JSONStatistics); ok {
+				s.RawJSON = ""
+				parsed = s
+			}
+
+			if !reflect.DeepEqual(m, parsed) {
+				t.Fatalf("%s: %q\n\twanted:\t%+v\n\thave:\t%+v", name, line, m, parsed)
+			}
+		}
+	}
+}
+
+func TestFormat_Parse(t *testing.T) {
+	g := generator{rand.New(rand.NewSource(2))}
+
+	// the types of cs2log.go also round-trip with the default patterns
+	for _, name := range []string{"PlayerKill", "PlayerAttack", "PlayerMoneyChange", "PlayerThrew", "PlayerBlinded", "ProjectileSpawned", "GameOver"} {
+		m := messageGenerators[name](g)
+		if parsed, err := Parse(Format(m)); err != nil || !reflect.DeepEqual(m, parsed) {
+			t.Errorf("%s: %q parsed to %+v, %v", name, Format(m), parsed, err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	ti := time.Date(2025, 8, 19, 15, 12, 44, 123*int(time.Millisecond), time.UTC)
+	attacker := Player{"Player-Name", 12, "[U:1:29384012]", "TERRORIST"}
+	victim := Player{"Player-Name", 10, "STEAM_1:1:0101010", "CT"}
+
+	tests := []struct {
+		m    Message
+		line string
+	}{
+		{
+			PlayerKill{NewMeta(ti, "PlayerKill"), attacker, Position{-413, 2130, -1}, victim, Position{-440, 1861, -95}, "ak47", true, false},
+			`L 08/19/2025 - 15:12:44.123: "Player-Name<12><[U:1:29384012]><TERRORIST>" [-413 2130 -1] killed "Player-Name<10><STEAM_1:1:0101010><CT>" [-440 1861 -95] with "ak47" (headshot)`,
+		},
+		{
+			PlayerBlinded{NewMeta(ti, "PlayerBlinded"), victim, attacker, 3.45, 163},
+			`L 08/19/2025 - 15:12:44.123: "Player-Name<12><[U:1:29384012]><TERRORIST>" blinded for 3.45 by "Player-Name<10><STEAM_1:1:0101010><CT>" from flashbang entindex 163`,
+		},
+		{
+			PlayerMoneyChange{NewMeta(ti, "PlayerMoneyChange"), attacker, Equation{2050, -1000, 1050}, "item_assaultsuit"},
+			`L 08/19/2025 - 15:12:44.123: "Player-Name<12><[U:1:29384012]><TERRORIST>" money change 2050-1000 = $1050 (tracked) (purchase: item_assaultsuit)`,
+		},
+		{
+			PlayerAccolade{NewMeta(ti, "PlayerAccolade"), "3k", Player{Name: "sh1ro", ID: 456}, 2, true},
+			`L 08/19/2025 - 15:12:44.123: ACCOLADE, FINAL: {3k}, sh1ro<456>, VALUE: 2.000000`,
+		},
+	}
+
+	for _, tt := range tests {
+		if line := Format(tt.m); line != tt.line {
+			t.Errorf("%s:\n\twanted:\t%s\n\thave:\t%s", tt.m.GetType(), tt.line, line)
+		}
+	}
+
+	if line := Format(nil); line != "" {
+		t.Errorf("Expected an empty line for unknown types, got %q", line)
+	}
+}
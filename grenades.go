@@ -0,0 +1,320 @@
+package cs2log
+
+import (
+	"sort"
+	"time"
+)
+
+// infernoDuration is how long the fire of a molotov burns
+const infernoDuration = 7 * time.Second
+
+// GrenadeOption configures AnalyzeGrenades
+type GrenadeOption func(*grenadeConfig)
+
+type grenadeConfig struct {
+	weapons     *WeaponRegistry
+	flashWindow time.Duration
+}
+
+// GrenadeWeapons sets the registry weapon names are normalized with,
+// the default is DefaultWeapons
+func GrenadeWeapons(r *WeaponRegistry) GrenadeOption {
+	return func(c *grenadeConfig) {
+		c.weapons = r
+	}
+}
+
+// GrenadeFlashWindow sets how soon after a flashbang a flashed enemy has to
+// be killed for the kill to count for it, see FlashKillWindow
+func GrenadeFlashWindow(d time.Duration) GrenadeOption {
+	return func(c *grenadeConfig) {
+		c.flashWindow = d
+	}
+}
+
+// Grenade is a grenade from the throw to its effects
+type Grenade struct {
+	Round  int    `json:"round"`
+	Player Player `json:"player"`
+	// Type is the weapon ID of the grenade, e.g. "hegrenade"
+	Type     string `json:"type"`
+	Entindex int    `json:"entindex,omitempty"`
+	// Thrown is the time of the throw, known from debug or projectile lines
+	Thrown time.Time `json:"thrown"`
+	// Detonated is the time the throw was logged, when the grenade went
+	// off, or of its first effect if the throw is missing
+	Detonated time.Time `json:"detonated"`
+	// Origin and Velocity are the start of the trajectory, known from
+	// GrenadeThrowDebug or ProjectileSpawned
+	Origin   *PositionFloat `json:"origin,omitempty"`
+	Velocity *Velocity      `json:"velocity,omitempty"`
+	// Landing is the position logged with PlayerThrew
+	Landing *Position `json:"landing,omitempty"`
+
+	// Damage is the health damage dealt to enemies without overkill
+	Damage int `json:"damage"`
+	// TeamDamage is the health damage dealt to teammates and the player
+	TeamDamage int `json:"team_damage"`
+	// Victims are the players damaged or blinded in order
+	Victims []Player `json:"victims"`
+	// Kills are the kills by the grenade itself, empty for flashbangs
+	Kills  []PlayerKill    `json:"kills"`
+	Blinds []PlayerBlinded `json:"blinds,omitempty"`
+	// FlashedKills are the kills of enemies a flashbang blinded, by the
+	// player or a teammate within the flash window
+	FlashedKills []PlayerKill `json:"flashed_kills,omitempty"`
+
+	// thrown is set once PlayerThrew is linked
+	thrown bool
+}
+
+// addVictim adds a player to the victims unless already one
+func (g *Grenade) addVictim(p Player) {
+	for _, v := range g.Victims {
+		if playerKey(v) == playerKey(p) {
+			return
+		}
+	}
+	g.Victims = append(g.Victims, p)
+}
+
+// PlayerUtility sums up the grenades of a player
+type PlayerUtility struct {
+	Player Player `json:"player"`
+	// Thrown counts the grenades by type
+	Thrown     map[string]int `json:"thrown"`
+	Damage     int            `json:"damage"`
+	TeamDamage int            `json:"team_damage"`
+	// Kills are the kills by the grenades themselves
+	Kills          int `json:"kills"`
+	EnemiesFlashed int `json:"enemies_flashed"`
+	// FlashedKills are the kills of enemies the player flashed
+	FlashedKills int `json:"flashed_kills"`
+}
+
+// GrenadeReport holds the grenades of a match
+type GrenadeReport struct {
+	// Grenades are in the order they went off
+	Grenades []Grenade `json:"grenades"`
+	// Players is keyed like the Scoreboard
+	Players map[string]PlayerUtility `json:"players"`
+}
+
+// Ranking returns the players sorted by utility damage, then enemies
+// flashed and name
+func (r GrenadeReport) Ranking() []PlayerUtility {
+	ranking := make([]PlayerUtility, 0, len(r.Players))
+	for _, p := range r.Players {
+		ranking = append(ranking, p)
+	}
+
+	sort.Slice(ranking, func(i, j int) bool {
+		a, b := ranking[i], ranking[j]
+		if a.Damage != b.Damage {
+			return a.Damage > b.Damage
+		}
+		if a.EnemiesFlashed != b.EnemiesFlashed {
+			return a.EnemiesFlashed > b.EnemiesFlashed
+		}
+		return a.Player.Name < b.Player.Name
+	})
+
+	return ranking
+}
+
+// AnalyzeGrenades tracks every grenade from its throw to the damage, kills
+// and blinds it caused, see SplitRounds. HE damage belongs to the HE of the
+// attacker going off at the same time, fire damage to the last molotov or
+// incendiary grenade of the attacker.
+func AnalyzeGrenades(rounds []Round, opts ...GrenadeOption) GrenadeReport {
+	cfg := grenadeConfig{weapons: DefaultWeapons(), flashWindow: flashKillWindow}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	report := GrenadeReport{Players: make(map[string]PlayerUtility)}
+
+	for _, r := range rounds {
+		for _, g := range linkGrenades(r, cfg) {
+			report.Grenades = append(report.Grenades, g)
+
+			key := playerKey(g.Player)
+			pu := report.Players[key]
+			pu.Player = g.Player
+			if pu.Thrown == nil {
+				pu.Thrown = make(map[string]int)
+			}
+			pu.Thrown[g.Type]++
+			pu.Damage += g.Damage
+			pu.TeamDamage += g.TeamDamage
+			pu.Kills += len(g.Kills)
+			pu.FlashedKills += len(g.FlashedKills)
+			for _, b := range g.Blinds {
+				if b.Victim.Side != b.Attacker.Side {
+					pu.EnemiesFlashed++
+				}
+			}
+			report.Players[key] = pu
+		}
+	}
+
+	return report
+}
+
+// grenadeClass returns the class grenades are linked by, molotovs and
+// incendiary grenades both burn as "inferno"
+func grenadeClass(weapon string) string {
+	switch weapon {
+	case "molotov", "incgrenade", "inferno":
+		return "molotov"
+	}
+	return weapon
+}
+
+// linkGrenades links the throws of a round to their effects. Flashbangs are
+// linked by linkFlashes, all other grenades by player and time.
+func linkGrenades(r Round, cfg grenadeConfig) []Grenade {
+	weapons := cfg.weapons
+	var grenades []*Grenade
+	// open holds the last grenade of each player and class
+	open := make(map[string]*Grenade)
+	// throws holds the debug lines of throws by name and class, spawns the
+	// molotov projectiles, both not linked yet
+	throws := make(map[string][]GrenadeThrowDebug)
+	var spawns []ProjectileSpawned
+
+	grenade := func(p Player, weapon string, t time.Time) *Grenade {
+		class := grenadeClass(weapon)
+		key := playerKey(p) + "/" + class
+
+		window := flashLinkWindow
+		if class == "molotov" {
+			window = infernoDuration
+		}
+
+		g, ok := open[key]
+		if ok && t.Sub(g.Detonated) <= window && t.Sub(g.Detonated) >= -flashLinkWindow {
+			return g
+		}
+
+		g = &Grenade{Round: r.Number, Player: p, Type: weapon, Detonated: t}
+		if weapon == "inferno" {
+			g.Type = "molotov"
+			if p.Side == "CT" {
+				g.Type = "incgrenade"
+			}
+		}
+
+		open[key] = g
+		grenades = append(grenades, g)
+		return g
+	}
+
+	// origin links the oldest debug line or projectile of a throw
+	origin := func(g *Grenade) {
+		key := g.Player.Name + "/" + grenadeClass(g.Type)
+		if debug := throws[key]; len(debug) > 0 {
+			g.Thrown = debug[0].Time
+			g.Origin = &debug[0].Position
+			g.Velocity = &debug[0].Velocity
+			throws[key] = debug[1:]
+			return
+		}
+
+		if grenadeClass(g.Type) == "molotov" && len(spawns) > 0 {
+			g.Thrown = spawns[0].Time
+			g.Origin = &spawns[0].Position
+			g.Velocity = &spawns[0].Velocity
+			spawns = spawns[1:]
+		}
+	}
+
+	health := make(healthTracker)
+
+	for _, msg := range r.Messages {
+		switch msg := msg.(type) {
+		case GrenadeThrowDebug:
+			key := msg.Player.Name + "/" + grenadeClass(weapons.Normalize(msg.GrenadeType))
+			throws[key] = append(throws[key], msg)
+
+		case ProjectileSpawned:
+			spawns = append(spawns, msg)
+
+		case PlayerThrew:
+			weapon := weapons.Normalize(msg.Grenade)
+			if weapon == "flashbang" {
+				continue
+			}
+
+			g := grenade(msg.Player, weapon, msg.Time)
+			if g.thrown {
+				// a second throw within the window is a new grenade
+				delete(open, playerKey(msg.Player)+"/"+grenadeClass(weapon))
+				g = grenade(msg.Player, weapon, msg.Time)
+			}
+
+			landing := msg.Position
+			g.Type = weapon
+			g.Entindex = msg.Entindex
+			g.Landing = &landing
+			g.thrown = true
+			origin(g)
+
+		case PlayerAttack:
+			damage := health.damage(msg)
+
+			weapon := weapons.Normalize(msg.Weapon)
+			if weapon != "hegrenade" && weapon != "inferno" {
+				continue
+			}
+
+			g := grenade(msg.Attacker, weapon, msg.Time)
+			if msg.Attacker.Side == msg.Victim.Side {
+				g.TeamDamage += damage
+			} else {
+				g.Damage += damage
+			}
+			g.addVictim(msg.Victim)
+
+		case PlayerKill:
+			weapon := weapons.Normalize(msg.Weapon)
+			if weapon == "hegrenade" || weapon == "inferno" {
+				g := grenade(msg.Attacker, weapon, msg.Time)
+				g.Kills = append(g.Kills, msg)
+			}
+		}
+	}
+
+	for _, f := range linkFlashes(r, cfg.flashWindow) {
+		g := &Grenade{
+			Round:        r.Number,
+			Player:       f.Player,
+			Type:         "flashbang",
+			Entindex:     f.Entindex,
+			Detonated:    f.Time,
+			FlashedKills: f.Kills,
+			Blinds:       f.Blinds,
+		}
+		if f.thrown {
+			landing := f.Position
+			g.Landing = &landing
+		}
+		for _, b := range f.Blinds {
+			g.addVictim(b.Victim)
+		}
+		origin(g)
+
+		grenades = append(grenades, g)
+	}
+
+	sort.SliceStable(grenades, func(i, j int) bool {
+		return grenades[i].Detonated.Before(grenades[j].Detonated)
+	})
+
+	linked := make([]Grenade, len(grenades))
+	for i, g := range grenades {
+		linked[i] = *g
+	}
+
+	return linked
+}
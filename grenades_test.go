@@ -0,0 +1,130 @@
+package cs2log
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeGrenades(t *testing.T) {
+	log := strings.Join([]string{
+		`World triggered "Match_Start" on "de_nuke"`,
+		`Starting Freeze period`,
+		`08/31/2025 - 16:30:00.000: World triggered "Round_Start"`,
+		`08/31/2025 - 16:30:02.000: "Magixx" sv_throw_hegrenade 100.0 200.0 0.0 10.0 20.0 30.0`,
+		`08/31/2025 - 16:30:04.000: ` + attack(magixx, zont1x, "hegrenade", 60, 40),
+		`08/31/2025 - 16:30:04.000: ` + attack(magixx, kyle, "hegrenade", 20, 80),
+		`08/31/2025 - 16:30:04.000: ` + attack(magixx, jame, "hegrenade", 5, 95),
+		`08/31/2025 - 16:30:04.000: ` + magixx + ` threw hegrenade [300 400 0]`,
+		`08/31/2025 - 16:30:10.000: Molotov projectile spawned at 1.000000 2.000000 3.000000, velocity 4.000000 5.000000 6.000000`,
+		`08/31/2025 - 16:30:11.000: ` + zont1x + ` threw molotov [500 600 0]`,
+		`08/31/2025 - 16:30:13.000: ` + attack(zont1x, jame, "inferno", 8, 87),
+		`08/31/2025 - 16:30:15.000: ` + attack(zont1x, jame, "inferno", 100, 0),
+		`08/31/2025 - 16:30:15.000: ` + kill(zont1x, jame, "inferno", false),
+		`08/31/2025 - 16:30:20.000: ` + threw(kyle, "flashbang", 50),
+		`08/31/2025 - 16:30:20.000: ` + blinded(magixx, kyle, 2, 50),
+		`08/31/2025 - 16:30:25.000: ` + attack(magixx, kyle, "inferno", 10, 70),
+		`08/31/2025 - 16:30:30.000: Team "CT" triggered "SFUI_Notice_Target_Saved" (CT "1") (T "0")`,
+		`08/31/2025 - 16:30:30.000: World triggered "Round_End"`,
+	}, "\n")
+
+	report := AnalyzeGrenades(SplitRounds(parseLog(t, log)))
+
+	if len(report.Grenades) != 4 {
+		t.Fatalf("Expected 4 grenades, got %+v", report.Grenades)
+	}
+
+	he := report.Grenades[0]
+	if he.Type != "hegrenade" || he.Player.Name != "Magixx" || he.Origin == nil || he.Origin.X != 100 || he.Landing == nil || he.Landing.X != 300 {
+		t.Errorf("Expected the HE of Magixx from its throw to its landing, got %+v", he)
+	}
+	if he.Damage != 80 || he.TeamDamage != 5 || len(he.Victims) != 3 || he.Thrown.Second() != 2 {
+		t.Errorf("Expected 80 damage and 5 team damage to 3 victims, got %+v", he)
+	}
+
+	molotov := report.Grenades[1]
+	if molotov.Type != "molotov" || molotov.Origin == nil || molotov.Origin.X != 1 || molotov.Damage != 95 || len(molotov.Kills) != 1 || len(molotov.Victims) != 1 {
+		t.Errorf("Expected the molotov of Zont1x to kill Jame with 95 damage, got %+v", molotov)
+	}
+
+	flash := report.Grenades[2]
+	if flash.Type != "flashbang" || len(flash.Blinds) != 1 || flash.Victims[0].Name != "Magixx" || flash.Landing == nil {
+		t.Errorf("Expected the flashbang of Kyle to blind Magixx, got %+v", flash)
+	}
+
+	// the fire damage of Magixx has no throw
+	fire := report.Grenades[3]
+	if fire.Type != "incgrenade" || fire.Landing != nil || fire.Damage != 10 {
+		t.Errorf("Expected an incendiary grenade of Magixx without throw, got %+v", fire)
+	}
+
+	ranking := report.Ranking()
+	if len(ranking) != 3 || ranking[0].Player.Name != "Zont1x" || ranking[1].Player.Name != "Magixx" || ranking[2].EnemiesFlashed != 1 {
+		t.Errorf("Unexpected ranking %+v", ranking)
+	}
+	if m := report.Players["[U:1:111]"]; m.Damage != 90 || m.TeamDamage != 5 || m.Thrown["hegrenade"] != 1 || m.Thrown["incgrenade"] != 1 {
+		t.Errorf("Unexpected utility of Magixx %+v", m)
+	}
+}
+
+func TestAnalyzeGrenades_Options(t *testing.T) {
+	log := strings.Join([]string{
+		`World triggered "Match_Start" on "de_nuke"`,
+		`Starting Freeze period`,
+		`08/31/2025 - 16:30:00.000: World triggered "Round_Start"`,
+		`08/31/2025 - 16:30:04.000: ` + attack(magixx, kyle, "frag", 20, 80),
+		`08/31/2025 - 16:30:20.000: ` + threw(magixx, "flashbang", 50),
+		`08/31/2025 - 16:30:20.000: ` + blinded(zont1x, magixx, 2, 50),
+		`08/31/2025 - 16:30:24.000: ` + kill(magixx, zont1x, "m4a1", false),
+		`08/31/2025 - 16:30:30.000: Team "CT" triggered "SFUI_Notice_Target_Saved" (CT "1") (T "0")`,
+		`08/31/2025 - 16:30:30.000: World triggered "Round_End"`,
+	}, "\n")
+	rounds := SplitRounds(parseLog(t, log))
+
+	report := AnalyzeGrenades(rounds)
+	if len(report.Grenades) != 1 || len(report.Grenades[0].FlashedKills) != 0 {
+		t.Fatalf("Expected only the flashbang without kills by default, got %+v", report.Grenades)
+	}
+
+	weapons := NewWeaponRegistry()
+	weapons.Register(Weapon{ID: "hegrenade", Category: CategoryGrenade, Aliases: []string{"frag"}})
+
+	report = AnalyzeGrenades(rounds, GrenadeWeapons(weapons), GrenadeFlashWindow(5*time.Second))
+	if len(report.Grenades) != 2 {
+		t.Fatalf("Expected 2 grenades, got %+v", report.Grenades)
+	}
+
+	if he := report.Grenades[0]; he.Type != "hegrenade" || he.Damage != 20 {
+		t.Errorf("Expected the frag to be an HE grenade with 20 damage, got %+v", he)
+	}
+	if flash := report.Grenades[1]; flash.Type != "flashbang" || len(flash.FlashedKills) != 1 {
+		t.Errorf("Expected the kill of Zont1x 4 seconds after the flash to count, got %+v", flash)
+	}
+}
+
+func TestAnalyzeGrenades_FlashedKills(t *testing.T) {
+	log := strings.Join([]string{
+		`World triggered "Match_Start" on "de_nuke"`,
+		`Starting Freeze period`,
+		`08/31/2025 - 16:30:00.000: World triggered "Round_Start"`,
+		`08/31/2025 - 16:30:20.000: ` + threw(magixx, "flashbang", 50),
+		`08/31/2025 - 16:30:20.000: ` + blinded(zont1x, magixx, 2, 50),
+		`08/31/2025 - 16:30:21.000: ` + kill(jame, zont1x, "awp", false),
+		`08/31/2025 - 16:30:30.000: Team "CT" triggered "SFUI_Notice_Target_Saved" (CT "1") (T "0")`,
+		`08/31/2025 - 16:30:30.000: World triggered "Round_End"`,
+	}, "\n")
+
+	report := AnalyzeGrenades(SplitRounds(parseLog(t, log)))
+
+	if len(report.Grenades) != 1 {
+		t.Fatalf("Expected 1 grenade, got %+v", report.Grenades)
+	}
+	if flash := report.Grenades[0]; len(flash.Kills) != 0 || len(flash.FlashedKills) != 1 {
+		t.Errorf("Expected the kill of Jame as flashed kill only, got %+v", flash)
+	}
+
+	// the kill of a teammate is no grenade kill of the thrower
+	if m := report.Players["[U:1:111]"]; m.Kills != 0 || m.FlashedKills != 1 || m.EnemiesFlashed != 1 {
+		t.Errorf("Expected Magixx to have 1 flashed kill and no grenade kills, got %+v", m)
+	}
+}
@@ -0,0 +1,149 @@
+package cs2log
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// httpLineLayout is the start of a line POSTed by logaddress_add_http,
+// which separates timestamp and message with " - " instead of ": "
+const httpLineLayout = "00/00/0000 - 00:00:00.000 - "
+
+// DefaultHTTPMaxBodySize is the size limit of a log batch if HTTPReceiver
+// doesn't set one, game servers send batches of a few kilobytes
+const DefaultHTTPMaxBodySize = 4 << 20
+
+// HTTPReceiver is an http.Handler receiving the log batches game servers POST
+// with logaddress_add_http. Every server instance gets its own ParserState,
+// so JSON statistics blocks spread over several requests are assembled and
+// servers logging to the same endpoint never mix.
+//
+// Servers are identified by the X-Server-Instance-Token header, falling back
+// to X-Server-Addr and the remote address of the request.
+type HTTPReceiver struct {
+	// Token authenticates the servers, passed as the "token" query parameter
+	// of the log address or as bearer token. Empty accepts every request.
+	Token string
+	// Parser parses the lines, nil uses ParseStateful
+	Parser *Parser
+	// Handler receives every record, tagged with the server instance.
+	// Lines that can't be parsed are passed as records with Err set.
+	Handler func(Record)
+	// MaxBodySize limits the size of a request in bytes, larger requests are
+	// rejected with 413 and none of their lines are parsed.
+	// Zero uses DefaultHTTPMaxBodySize.
+	MaxBodySize int64
+	// MaxServers limits the number of server instances whose parse state is
+	// kept, the state of the instance heard from least recently is dropped
+	// first. Zero uses DefaultMaxServers.
+	MaxServers int
+
+	servers serverStates
+}
+
+// ServeHTTP parses the lines of a log batch
+func (h *HTTPReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !h.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if r.ContentLength > h.maxBodySize() {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	var lines []string
+	var err error
+	reader := newLineReader(http.MaxBytesReader(w, r.Body, h.maxBodySize()))
+	for {
+		var line string
+		if line, err = reader.next(); err != nil {
+			break
+		}
+		lines = append(lines, normalizeHTTPLine(line))
+	}
+
+	// a rejected batch is sent again, so its lines are only parsed once it
+	// was read completely
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if err != io.EOF {
+		http.Error(w, "reading body failed", http.StatusBadRequest)
+		return
+	}
+
+	server := httpServerID(r)
+	h.servers.get(server, h.Parser, h.MaxServers).feed(server, lines, h.handle)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Forget drops the parse state of a server instance, e.g. after it shut down
+func (h *HTTPReceiver) Forget(server string) {
+	h.servers.forget(server)
+}
+
+func (h *HTTPReceiver) maxBodySize() int64 {
+	if h.MaxBodySize > 0 {
+		return h.MaxBodySize
+	}
+	return DefaultHTTPMaxBodySize
+}
+
+func (h *HTTPReceiver) handle(rec Record) {
+	if h.Handler != nil {
+		h.Handler(rec)
+	}
+}
+
+// authorized reports whether the request carries the token
+func (h *HTTPReceiver) authorized(r *http.Request) bool {
+	if h.Token == "" {
+		return true
+	}
+
+	token := r.URL.Query().Get("token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.Token)) == 1
+}
+
+// httpServerID returns the identity of the server instance that sent r
+func httpServerID(r *http.Request) string {
+	if id := r.Header.Get("X-Server-Instance-Token"); id != "" {
+		return id
+	}
+
+	if addr := r.Header.Get("X-Server-Addr"); addr != "" {
+		return addr
+	}
+
+	return r.RemoteAddr
+}
+
+// normalizeHTTPLine rewrites the " - " after the timestamp to ": ",
+// so the line has the format of the log file
+func normalizeHTTPLine(line string) string {
+	if len(line) < len(httpLineLayout) || !matchesLayout(line[:len(httpLineLayout)], httpLineLayout) {
+		return line
+	}
+
+	n := len(httpLineLayout)
+	return line[:n-3] + ": " + line[n:]
+}
@@ -0,0 +1,132 @@
+package cs2log
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPReceiver(t *testing.T) {
+	var records []Record
+	h := &HTTPReceiver{
+		Token:   "s3cret",
+		Handler: func(r Record) { records = append(records, r) },
+	}
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	post := func(instance, query, auth, body string) int {
+		req, err := http.NewRequest(http.MethodPost, srv.URL+query, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("X-Server-Instance-Token", instance)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	// the JSON block of server a spans two requests, interleaved with server b
+	if code := post("a", "?token=s3cret", "", "08/31/2025 - 16:30:18.000 - JSON_BEGIN{\n"+
+		"08/31/2025 - 16:30:18.000 - \"name\": \"round_stats\",\n"+
+		"08/31/2025 - 16:30:18.000 - \"round_number\" : \"4\",\n"); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+
+	if code := post("b", "", "Bearer s3cret", "08/31/2025 - 16:30:19.000 - World triggered \"Round_Start\"\n"); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+
+	if code := post("a", "?token=s3cret", "", "08/31/2025 - 16:30:18.000 - \"players\" : {\n"+
+		"08/31/2025 - 16:30:18.000 - }}JSON_END\n"); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+
+	if code := post("a", "?token=wrong", "", "08/31/2025 - 16:30:19.000 - Match unpaused\n"); code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for wrong token, got %d", code)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d: %+v", len(records), records)
+	}
+
+	if records[0].Server != "b" || records[0].Message.GetType() != "WorldRoundStart" {
+		t.Errorf("Expected WorldRoundStart from b, got %+v", records[0])
+	}
+
+	stats, ok := records[1].Message.(JSONStatistics)
+	if !ok || records[1].Server != "a" || stats.RoundNumber != 4 {
+		t.Errorf("Expected JSONStatistics of round 4 from a, got %+v", records[1])
+	}
+}
+
+func TestHTTPReceiver_Method(t *testing.T) {
+	h := &HTTPReceiver{}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", rec.Code)
+	}
+}
+
+func TestHTTPReceiver_MaxBodySize(t *testing.T) {
+	var records []Record
+	h := &HTTPReceiver{
+		MaxBodySize: 100,
+		Handler:     func(r Record) { records = append(records, r) },
+	}
+
+	line := "08/31/2025 - 16:30:19.000 - World triggered \"Round_Start\"\n"
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(line)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat(line, 10))))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413, got %d", rec.Code)
+	}
+
+	// without Content-Length the limit is found while reading
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat(line, 10)))
+	req.ContentLength = -1
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 without Content-Length, got %d", rec.Code)
+	}
+
+	// the server sends rejected batches again, so none of their lines are parsed
+	if len(records) != 1 {
+		t.Errorf("Expected 1 record, got %d", len(records))
+	}
+}
+
+func TestNormalizeHTTPLine(t *testing.T) {
+	tests := map[string]string{
+		"08/31/2025 - 16:30:19.000 - Match unpaused": "08/31/2025 - 16:30:19.000: Match unpaused",
+		"08/31/2025 - 16:30:19.000: Match unpaused":  "08/31/2025 - 16:30:19.000: Match unpaused",
+		"garbage": "garbage",
+	}
+
+	for in, expected := range tests {
+		if got := normalizeHTTPLine(in); got != expected {
+			t.Errorf("normalizeHTTPLine(%q) = %q, expected %q", in, got, expected)
+		}
+	}
+}
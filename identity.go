@@ -0,0 +1,194 @@
+package cs2log
+
+import (
+	"strconv"
+	"time"
+)
+
+// Identity is a person or bot across a log
+type Identity struct {
+	// Key is stable for the whole log, the SteamID3 of players and
+	// "BOT:<name>#<n>" for the n-th bot instance with that name
+	Key     string  `json:"key"`
+	SteamID SteamID `json:"steam_id"`
+	// Name is the last known name, Names all names in the order used
+	Name  string   `json:"name"`
+	Names []string `json:"names"`
+	// IDs are the user IDs of the connections in the order used
+	IDs []int `json:"ids"`
+	// Connected is set while the player is on the server
+	Connected bool      `json:"connected"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// Bot reports whether the identity is a bot instance
+func (i Identity) Bot() bool {
+	return i.SteamID.IsBot()
+}
+
+// addName adds a name unless already used
+func (i *Identity) addName(name string) {
+	i.Name = name
+	for _, n := range i.Names {
+		if n == name {
+			return
+		}
+	}
+	i.Names = append(i.Names, name)
+}
+
+// addID adds a user ID unless already used
+func (i *Identity) addID(id int) {
+	for _, n := range i.IDs {
+		if n == id {
+			return
+		}
+	}
+	i.IDs = append(i.IDs, id)
+}
+
+// IdentityResolver maps the players of messages to stable identities.
+// Players are identified by their SteamID in any format, so reconnects with
+// a new user ID and name changes keep the identity. Bots all share the
+// SteamID "BOT" and reuse names, every bot from connecting to disconnecting
+// is an instance of its own. Players without a valid SteamID, like those of
+// accolades, are found by their user ID while connected.
+type IdentityResolver struct {
+	identities map[string]*Identity
+	// order holds the identities in the order they were first seen
+	order []*Identity
+	// slots holds the identity of every user ID in use
+	slots map[int]*Identity
+	// bots counts the instances by bot name
+	bots map[string]int
+}
+
+// NewIdentityResolver creates a resolver without identities
+func NewIdentityResolver() *IdentityResolver {
+	return &IdentityResolver{
+		identities: make(map[string]*Identity),
+		slots:      make(map[int]*Identity),
+		bots:       make(map[string]int),
+	}
+}
+
+// Update resolves the players of a message and returns their identities,
+// attacker first, see Resolve. Disconnects free the user ID, so the next bot
+// with the same name is a new instance.
+func (r *IdentityResolver) Update(msg Message) []Identity {
+	players := messagePlayers(msg)
+	identities := make([]Identity, 0, len(players))
+	t := msg.GetTime()
+
+	for _, p := range players {
+		i := r.resolve(p, t)
+		if i == nil {
+			continue
+		}
+		identities = append(identities, *i)
+	}
+
+	switch msg := msg.(type) {
+	case PlayerDisconnected:
+		if i := r.lookup(msg.Player); i != nil {
+			i.Connected = false
+			if r.slots[msg.Player.ID] == i {
+				delete(r.slots, msg.Player.ID)
+			}
+			identities[0] = *i
+		}
+	case PlayerNameChanged:
+		if i := r.lookup(msg.Player); i != nil {
+			i.addName(msg.Name)
+			identities[0] = *i
+		}
+	}
+
+	return identities
+}
+
+// Resolve returns the identity of a player seen in an update, false for
+// players never seen with a valid SteamID or a user ID in use
+func (r *IdentityResolver) Resolve(p Player) (Identity, bool) {
+	i := r.lookup(p)
+	if i == nil {
+		return Identity{}, false
+	}
+	return *i, true
+}
+
+// Key returns the key of the identity of a player, players that can't be
+// resolved are keyed like the Scoreboard does
+func (r *IdentityResolver) Key(p Player) string {
+	if i := r.lookup(p); i != nil {
+		return i.Key
+	}
+	return playerKey(p)
+}
+
+// Identities returns all identities in the order they were first seen
+func (r *IdentityResolver) Identities() []Identity {
+	identities := make([]Identity, len(r.order))
+	for n, i := range r.order {
+		identities[n] = *i
+	}
+	return identities
+}
+
+// lookup finds the identity of a player without creating one
+func (r *IdentityResolver) lookup(p Player) *Identity {
+	id := p.Steam()
+
+	if id.IsAccount() {
+		return r.identities[id.SteamID3()]
+	}
+
+	// a bot or player with another name in the slot is someone else
+	slot, ok := r.slots[p.ID]
+	if !ok || slot.Name != p.Name {
+		return nil
+	}
+
+	if (id.IsBot() || id.IsGOTV()) && slot.SteamID.Type != id.Type {
+		return nil
+	}
+
+	return slot
+}
+
+// resolve finds or creates the identity of a player and takes note of its
+// user ID and name
+func (r *IdentityResolver) resolve(p Player, t time.Time) *Identity {
+	id := p.Steam()
+
+	i := r.lookup(p)
+	if i == nil {
+		switch {
+		case id.IsAccount():
+			i = &Identity{Key: id.SteamID3(), SteamID: id}
+		case id.IsBot(), id.IsGOTV():
+			r.bots[p.Name]++
+			i = &Identity{Key: botSteamID + ":" + p.Name + "#" + strconv.Itoa(r.bots[p.Name]), SteamID: id}
+		default:
+			return nil
+		}
+
+		i.FirstSeen = t
+		r.identities[i.Key] = i
+		r.order = append(r.order, i)
+	}
+
+	// a new connection of a player frees the user ID of the old one
+	if old, ok := r.slots[p.ID]; ok && old != i {
+		old.Connected = false
+	}
+	r.slots[p.ID] = i
+
+	i.addName(p.Name)
+	i.addID(p.ID)
+	i.Connected = true
+	i.LastSeen = t
+
+	return i
+}
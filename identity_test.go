@@ -0,0 +1,79 @@
+package cs2log
+
+import "testing"
+
+func TestIdentityResolver(t *testing.T) {
+	messages := parseLog(t, `
+		"Magixx<2><[U:1:111]><>" connected, address "10.0.0.1:27005"
+		"Magixx<2><[U:1:111]><>" STEAM USERID validated
+		"Magixx<2><[U:1:111]><>" entered the game
+		"Kyle<5><BOT><>" connected, address ""
+		"Kyle<5><BOT><>" entered the game
+		"Magixx<2><[U:1:111]><CT>" changed name to "m4gixx"
+		"Kyle<5><BOT><TERRORIST>" disconnected (reason "Kicked by Console")
+		"Kyle<6><BOT><>" connected, address ""
+		"m4gixx<2><[U:1:111]><CT>" disconnected (reason "Disconnect")
+		"m4gixx<9><STEAM_1:1:55><>" connected, address "10.0.0.1:27005"
+		ACCOLADE, FINAL: {3k}, m4gixx<9>, VALUE: 3.000000
+		"Kyle<6><BOT><TERRORIST>" [0 0 0] killed "m4gixx<9><STEAM_1:1:55><CT>" [0 0 0] with "glock"
+	`)
+
+	r := NewIdentityResolver()
+	var last []Identity
+	for _, msg := range messages {
+		last = r.Update(msg)
+	}
+
+	if len(last) != 2 || last[0].Key != "BOT:Kyle#2" || last[1].Key != "[U:1:111]" {
+		t.Fatalf("Unexpected identities of the kill: %+v", last)
+	}
+
+	identities := r.Identities()
+	if len(identities) != 3 {
+		t.Fatalf("Expected 3 identities, got %+v", identities)
+	}
+
+	magixx := identities[0]
+	if magixx.Key != "[U:1:111]" || magixx.Name != "m4gixx" || !magixx.Connected {
+		t.Errorf("Unexpected identity %+v", magixx)
+	}
+	if len(magixx.Names) != 2 || len(magixx.IDs) != 2 || magixx.IDs[1] != 9 {
+		t.Errorf("Expected both names and user IDs, got %v and %v", magixx.Names, magixx.IDs)
+	}
+	if magixx.SteamID.AccountID() != 111 {
+		t.Errorf("Expected account 111, got %+v", magixx.SteamID)
+	}
+
+	if kyle := identities[1]; kyle.Key != "BOT:Kyle#1" || !kyle.Bot() || kyle.Connected {
+		t.Errorf("Expected the first Kyle to be disconnected, got %+v", kyle)
+	}
+	if kyle := identities[2]; kyle.Key != "BOT:Kyle#2" || !kyle.Connected {
+		t.Errorf("Expected the second Kyle to be a new instance, got %+v", kyle)
+	}
+
+	accolade := NewPlayer("m4gixx", "9", "", "")
+	if key := r.Key(accolade); key != "[U:1:111]" {
+		t.Errorf("Expected the accolade to resolve by user ID, got %s", key)
+	}
+	if _, ok := r.Resolve(NewPlayer("Someone", "9", "", "")); ok {
+		t.Error("Expected a player with another name not to resolve")
+	}
+	if key := r.Key(NewPlayer("Kyle", "5", "BOT", "")); key != "BOT:Kyle" {
+		t.Errorf("Expected a bot not connected to be keyed like the Scoreboard, got %s", key)
+	}
+}
+
+func TestPlayerNameChanged(t *testing.T) {
+	msg, err := ParseEnhanced(`08/19/2025 - 15:12:44.000: "Magixx<2><[U:1:111]><CT>" changed name to "m4gixx"`)
+	if err != nil {
+		t.Fatalf("Failed to parse PlayerNameChanged: %v", err)
+	}
+
+	changed, ok := msg.(PlayerNameChanged)
+	if !ok {
+		t.Fatalf("Expected PlayerNameChanged, got %T", msg)
+	}
+	if changed.Player.Name != "Magixx" || changed.Player.Side != "CT" || changed.Name != "m4gixx" {
+		t.Errorf("Unexpected message %+v", changed)
+	}
+}
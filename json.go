@@ -0,0 +1,153 @@
+package cs2log
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrorTypeExists error when a message type is already registered
+	ErrorTypeExists = errors.New("message type already registered")
+	// ErrorUnknownType error when JSON holds a message type that is not registered
+	ErrorUnknownType = errors.New("unknown message type")
+)
+
+// TypeRegistry maps the message types of Meta.Type to the Go types JSON is
+// decoded into. A registry is safe for concurrent use.
+type TypeRegistry struct {
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+}
+
+// NewTypeRegistry creates an empty registry
+func NewTypeRegistry() *TypeRegistry {
+	return &TypeRegistry{types: make(map[string]reflect.Type)}
+}
+
+// Register adds a message type, JSON with the given type is decoded into a
+// new value of the type of prototype. Prototypes may be values or pointers,
+// decoded messages are of the same kind.
+func (r *TypeRegistry) Register(name string, prototype Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.types[name]; ok {
+		return ErrorTypeExists
+	}
+
+	r.types[name] = reflect.TypeOf(prototype)
+	return nil
+}
+
+// Remove removes a message type and reports whether it was registered
+func (r *TypeRegistry) Remove(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.types[name]
+	delete(r.types, name)
+	return ok
+}
+
+// Types returns the names of all registered message types sorted
+func (r *TypeRegistry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.types))
+	for name := range r.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Clone returns an independent copy of the registry
+func (r *TypeRegistry) Clone() *TypeRegistry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c := NewTypeRegistry()
+	for name, t := range r.types {
+		c.types[name] = t
+	}
+	return c
+}
+
+// FromJSON decodes a message encoded with ToJSON into the type registered
+// for its "type" field
+func (r *TypeRegistry) FromJSON(data []byte) (Message, error) {
+	var meta struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	t, ok := r.types[meta.Type]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrorUnknownType, meta.Type)
+	}
+
+	ptr := t.Kind() == reflect.Ptr
+	if ptr {
+		t = t.Elem()
+	}
+
+	v := reflect.New(t)
+	if err := json.Unmarshal(data, v.Interface()); err != nil {
+		return nil, err
+	}
+
+	if ptr {
+		return v.Interface().(Message), nil
+	}
+	return v.Elem().Interface().(Message), nil
+}
+
+// builtinTypes are the message types of the built-in patterns
+var builtinTypes = []Message{
+	ServerMessage{}, FreezTimeStart{}, WorldMatchStart{}, WorldRoundStart{},
+	WorldRoundRestart{}, WorldRoundEnd{}, WorldGameCommencing{}, TeamScored{},
+	TeamNotice{}, PlayerConnected{}, PlayerDisconnected{}, PlayerEntered{},
+	PlayerBanned{}, PlayerSwitched{}, PlayerSay{}, PlayerPurchase{}, PlayerKill{},
+	PlayerKillAssist{}, PlayerFlashAssist{}, PlayerAttack{}, PlayerKilledBomb{},
+	PlayerKilledSuicide{}, PlayerPickedUp{}, PlayerDropped{}, PlayerMoneyChange{},
+	PlayerBombGot{}, PlayerBombPlanted{}, PlayerBombDropped{}, PlayerBombBeginDefuse{},
+	PlayerBombDefused{}, PlayerThrew{}, PlayerBlinded{}, ProjectileSpawned{},
+	GameOver{}, Unknown{},
+
+	PlayerLeftBuyzone{}, PlayerValidated{}, PlayerNameChanged{}, PlayerAccolade{},
+	MatchStatus{}, TeamPlaying{}, MatchPause{}, GrenadeThrowDebug{}, ServerCvar{},
+	RconCommand{}, LoadingMap{}, StartedMap{}, LogFile{}, MatchStatusTeam{},
+	TriggeredEvent{}, ChatCommand{}, GameOverDetailed{}, BombEvent{}, FreezePeriod{},
+	WarmupStart{}, WarmupEnd{}, JSONStatistics{},
+}
+
+// DefaultTypes returns a new registry with the message types of the built-in
+// patterns, registered by their Go type name like Meta.Type
+func DefaultTypes() *TypeRegistry {
+	r := NewTypeRegistry()
+	for _, m := range builtinTypes {
+		r.Register(reflect.TypeOf(m).Name(), m)
+	}
+	return r
+}
+
+// defaultTypes holds the types FromJSON decodes
+var defaultTypes = DefaultTypes()
+
+// FromJSON decodes a message encoded with ToJSON into its built-in type,
+// see DefaultTypes. Use a TypeRegistry to decode custom message types.
+// Maps and slices marked omitempty decode as nil when they were empty.
+func FromJSON(data []byte) (Message, error) {
+	return defaultTypes.FromJSON(data)
+}
@@ -0,0 +1,118 @@
+package cs2log
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFromJSON_RoundTrip(t *testing.T) {
+	g := generator{rand.New(rand.NewSource(3))}
+
+	for name, generate := range messageGenerators {
+		for i := 0; i < 20; i++ {
+			m := generate(g)
+
+			// empty data is omitted and decodes as nil
+			if e, ok := m.(TriggeredEvent); ok && i%2 == 0 {
+				e.Data = map[string]string{"1": g.word()}
+				m = e
+			} else if ok {
+				e.Data = nil
+				m = e
+			}
+
+			decoded, err := FromJSON([]byte(ToJSON(m)))
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if !reflect.DeepEqual(m, decoded) {
+				t.Fatalf("%s:\n\twanted:\t%+v\n\thave:\t%+v", name, m, decoded)
+			}
+		}
+	}
+
+	ti := time.Date(2025, 8, 31, 16, 30, 0, 0, time.UTC)
+	for _, m := range []Message{
+		MatchStatusTeam{NewMeta(ti, "MatchStatusTeam"), "CT", "team_SHESKY"},
+		FreezePeriod{NewMeta(ti, "FreezePeriod"), "start"},
+	} {
+		if decoded, err := FromJSON([]byte(ToJSON(m))); err != nil || !reflect.DeepEqual(m, decoded) {
+			t.Errorf("%s: decoded to %+v, %v", m.GetType(), decoded, err)
+		}
+	}
+}
+
+func TestFromJSON_Parsed(t *testing.T) {
+	messages := parseLog(t, ratingLog)
+
+	for _, m := range messages {
+		decoded, err := FromJSON([]byte(ToJSON(m)))
+		if err != nil {
+			t.Fatalf("%s: %v", m.GetType(), err)
+		}
+		if !reflect.DeepEqual(m, decoded) {
+			t.Errorf("%s:\n\twanted:\t%+v\n\thave:\t%+v", m.GetType(), m, decoded)
+		}
+	}
+}
+
+func TestFromJSON_Errors(t *testing.T) {
+	if _, err := FromJSON([]byte(`{"type":"Unheard"}`)); !errors.Is(err, ErrorUnknownType) {
+		t.Errorf("Expected ErrorUnknownType, got %v", err)
+	}
+	if _, err := FromJSON([]byte(`{"time":"2025-08-31T16:30:00Z"}`)); !errors.Is(err, ErrorUnknownType) {
+		t.Errorf("Expected ErrorUnknownType without type, got %v", err)
+	}
+	if _, err := FromJSON([]byte(`{"type":`)); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+	if _, err := FromJSON([]byte(`{"type":"PlayerKill","weapon":5}`)); err == nil {
+		t.Error("Expected an error for a field of the wrong type")
+	}
+}
+
+type reloaded struct {
+	Meta
+	Weapon string `json:"weapon"`
+}
+
+func TestTypeRegistry(t *testing.T) {
+	r := DefaultTypes()
+
+	if err := r.Register("PlayerKill", reloaded{}); err != ErrorTypeExists {
+		t.Errorf("Expected ErrorTypeExists, got %v", err)
+	}
+	if err := r.Register("Reloaded", reloaded{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register("ReloadedPtr", &reloaded{}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := reloaded{NewMeta(time.Date(2025, 8, 31, 16, 30, 0, 0, time.UTC), "Reloaded"), "ak47"}
+	decoded, err := r.FromJSON([]byte(ToJSON(m)))
+	if err != nil || !reflect.DeepEqual(m, decoded) {
+		t.Errorf("Expected %+v, got %+v, %v", m, decoded, err)
+	}
+
+	m.Type = "ReloadedPtr"
+	decoded, err = r.FromJSON([]byte(ToJSON(m)))
+	if p, ok := decoded.(*reloaded); err != nil || !ok || *p != m {
+		t.Errorf("Expected a pointer to %+v, got %+v, %v", m, decoded, err)
+	}
+
+	if _, err := FromJSON([]byte(ToJSON(m))); !errors.Is(err, ErrorUnknownType) {
+		t.Errorf("Expected the default types not to know custom types, got %v", err)
+	}
+
+	c := r.Clone()
+	if !r.Remove("Reloaded") || r.Remove("Reloaded") {
+		t.Error("Expected Reloaded to be removed once")
+	}
+	if len(c.Types()) != len(r.Types())+1 {
+		t.Errorf("Expected the clone to be independent, got %d and %d types", len(c.Types()), len(r.Types()))
+	}
+}
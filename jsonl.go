@@ -0,0 +1,286 @@
+package cs2log
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// ErrorNotObject error when a message is not encoded as a JSON object, so the
+// origin of its record can't be added
+var ErrorNotObject = errors.New("message is not encoded as a JSON object")
+
+// gzipMagic are the first bytes of gzip compressed data
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// JSONLOption configures a JSONLEncoder or JSONLDecoder
+type JSONLOption func(*jsonlConfig)
+
+type jsonlConfig struct {
+	gzip  bool
+	types *TypeRegistry
+}
+
+// JSONLGzip compresses the output of an encoder with gzip. Decoders detect
+// compressed input themselves.
+func JSONLGzip() JSONLOption {
+	return func(c *jsonlConfig) {
+		c.gzip = true
+	}
+}
+
+// JSONLTypes decodes messages with the registry instead of DefaultTypes
+func JSONLTypes(r *TypeRegistry) JSONLOption {
+	return func(c *jsonlConfig) {
+		c.types = r
+	}
+}
+
+// jsonlOrigin is the source of a record, added to its message as "origin"
+type jsonlOrigin struct {
+	Server string `json:"server,omitempty"`
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+}
+
+// JSONLEncoder writes messages as JSON Lines, one message per line encoded
+// like ToJSON. Records keep their source as an "origin" object with server,
+// file and line next to the fields of the message, so every line can still be
+// decoded with FromJSON. Output is buffered, call Close when done.
+type JSONLEncoder struct {
+	w   *bufio.Writer
+	gz  *gzip.Writer
+	buf bytes.Buffer
+	enc *json.Encoder
+	err error
+}
+
+// NewJSONLEncoder returns an encoder writing to w
+func NewJSONLEncoder(w io.Writer, opts ...JSONLOption) *JSONLEncoder {
+	c := jsonlConfig{}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	e := &JSONLEncoder{}
+	e.enc = newJSONEncoder(&e.buf)
+
+	if c.gzip {
+		e.gz = gzip.NewWriter(w)
+		w = e.gz
+	}
+	e.w = bufio.NewWriter(w)
+
+	return e
+}
+
+// Encode writes a message without origin
+func (e *JSONLEncoder) Encode(m Message) error {
+	return e.EncodeRecord(Record{Message: m})
+}
+
+// EncodeRecord writes the message of a record with its server, file and line
+// as origin, the origin is left out if none of them is set. Records without a
+// message, like those of lines that failed to parse, are skipped.
+func (e *JSONLEncoder) EncodeRecord(rec Record) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	if rec.Message == nil {
+		return nil
+	}
+
+	e.buf.Reset()
+	if err := e.enc.Encode(rec.Message); err != nil {
+		return err
+	}
+
+	if origin := (jsonlOrigin{rec.Server, rec.File, rec.Line}); origin != (jsonlOrigin{}) {
+		if err := e.addOrigin(origin); err != nil {
+			return err
+		}
+	}
+
+	_, e.err = e.w.Write(e.buf.Bytes())
+	return e.err
+}
+
+// addOrigin adds the origin to the encoded message in the buffer
+func (e *JSONLEncoder) addOrigin(origin jsonlOrigin) error {
+	line := e.buf.Bytes()
+	n := len(line)
+
+	// the encoder ends objects with "}\n"
+	if n < 3 || line[0] != '{' || line[n-2] != '}' {
+		return ErrorNotObject
+	}
+
+	empty := line[n-3] == '{'
+	e.buf.Truncate(n - 2)
+	if !empty {
+		e.buf.WriteByte(',')
+	}
+	e.buf.WriteString(`"origin":`)
+
+	if err := e.enc.Encode(origin); err != nil {
+		return err
+	}
+
+	e.buf.Truncate(e.buf.Len() - 1)
+	e.buf.WriteString("}\n")
+
+	return nil
+}
+
+// Flush writes buffered lines to the underlying writer, compressed output
+// can be decoded up to this point
+func (e *JSONLEncoder) Flush() error {
+	if e.err != nil {
+		return e.err
+	}
+
+	if e.err = e.w.Flush(); e.err != nil {
+		return e.err
+	}
+
+	if e.gz != nil {
+		e.err = e.gz.Flush()
+	}
+	return e.err
+}
+
+// Close flushes buffered lines and ends the gzip stream, it doesn't close the
+// underlying writer
+func (e *JSONLEncoder) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+
+	if e.err = e.w.Flush(); e.err != nil {
+		return e.err
+	}
+
+	if e.gz != nil {
+		e.err = e.gz.Close()
+	}
+	return e.err
+}
+
+// JSONLDecoder reads JSON Lines written by a JSONLEncoder back into records,
+// gzip compressed input is detected by its header. The origin of a line sets
+// Server, File and Line of the record.
+//
+// Decoding stops at the end of the input or at the first read error. Lines
+// that can't be decoded don't stop the decoder, they are returned as records
+// with Err set and Line and Raw of the JSON line. Empty lines are skipped.
+type JSONLDecoder struct {
+	r     io.Reader
+	lines *lineReader
+	types *TypeRegistry
+
+	record Record
+	err    error
+	done   bool
+}
+
+// NewJSONLDecoder returns a decoder reading from r
+func NewJSONLDecoder(r io.Reader, opts ...JSONLOption) *JSONLDecoder {
+	c := jsonlConfig{types: defaultTypes}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return &JSONLDecoder{r: r, types: c.types}
+}
+
+// Scan advances to the next record, which is then available through Record.
+// It returns false when the input is exhausted or a read error occurred.
+func (d *JSONLDecoder) Scan() bool {
+	if d.lines == nil && !d.done {
+		d.open()
+	}
+
+	for !d.done {
+		line, err := d.lines.next()
+
+		if err != nil {
+			d.done = true
+			if err != io.EOF {
+				d.err = err
+			}
+			return false
+		}
+
+		if line == "" {
+			continue
+		}
+
+		d.record = d.decode(line, d.lines.line)
+		return true
+	}
+
+	return false
+}
+
+// open detects compressed input and sets up the line reader
+func (d *JSONLDecoder) open() {
+	br := bufio.NewReader(d.r)
+
+	magic, _ := br.Peek(len(gzipMagic))
+	if !bytes.Equal(magic, gzipMagic) {
+		d.lines = &lineReader{r: br}
+		return
+	}
+
+	gz, err := gzip.NewReader(br)
+	if err != nil {
+		d.done = true
+		d.err = err
+		return
+	}
+	d.lines = newLineReader(gz)
+}
+
+// decode decodes line number n
+func (d *JSONLDecoder) decode(line string, n int) Record {
+	data := []byte(line)
+
+	msg, err := d.types.FromJSON(data)
+
+	var origin struct {
+		Origin jsonlOrigin `json:"origin"`
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &origin)
+	}
+
+	if err != nil {
+		return Record{Line: n, Raw: line, Err: err}
+	}
+
+	return Record{
+		Message: msg,
+		Line:    origin.Origin.Line,
+		Server:  origin.Origin.Server,
+		File:    origin.Origin.File,
+	}
+}
+
+// Record returns the most recent record read by Scan
+func (d *JSONLDecoder) Record() Record {
+	return d.record
+}
+
+// Message returns the message of the most recent record, nil if it has an error
+func (d *JSONLDecoder) Message() Message {
+	return d.record.Message
+}
+
+// Err returns the first read error, decode errors are reported by Record
+func (d *JSONLDecoder) Err() error {
+	return d.err
+}
@@ -0,0 +1,158 @@
+package cs2log
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONL_RoundTrip(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		g := generator{rand.New(rand.NewSource(4))}
+
+		var records []Record
+		for name, generate := range messageGenerators {
+			for i := 0; i < 5; i++ {
+				m := generate(g)
+
+				// empty data is omitted and decodes as nil
+				if e, ok := m.(TriggeredEvent); ok {
+					e.Data = nil
+					m = e
+				}
+
+				rec := Record{Message: m}
+				if i%2 == 0 {
+					rec.Server, rec.File, rec.Line = "10.0.0.1:27015", name+".log", len(records)+1
+				}
+				records = append(records, rec)
+			}
+		}
+
+		var opts []JSONLOption
+		if compress {
+			opts = append(opts, JSONLGzip())
+		}
+
+		buf := &bytes.Buffer{}
+		e := NewJSONLEncoder(buf, opts...)
+		for _, rec := range records {
+			if err := e.EncodeRecord(rec); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := e.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if gz := bytes.HasPrefix(buf.Bytes(), gzipMagic); gz != compress {
+			t.Errorf("Expected gzip %v, got %v", compress, gz)
+		}
+
+		d := NewJSONLDecoder(buf)
+		n := 0
+		for d.Scan() {
+			if n >= len(records) {
+				t.Fatalf("Expected %d records, got more", len(records))
+			}
+			if have := d.Record(); !reflect.DeepEqual(records[n], have) {
+				t.Fatalf("record %d:\n\twanted:\t%+v\n\thave:\t%+v", n, records[n], have)
+			}
+			n++
+		}
+		if err := d.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if n != len(records) {
+			t.Errorf("Expected %d records, got %d", len(records), n)
+		}
+	}
+}
+
+func TestJSONLEncoder(t *testing.T) {
+	ti := time.Date(2025, 8, 31, 16, 30, 0, 0, time.UTC)
+	say := PlayerSay{NewMeta(ti, "PlayerSay"), Player{"<b>Player</b>", 3, "[U:1:29384012]", "CT"}, "a && b", false}
+	rcon := RconCommand{NewMeta(ti, "RconCommand"), "10.0.0.2:51234", "status"}
+
+	buf := &bytes.Buffer{}
+	e := NewJSONLEncoder(buf)
+	e.Encode(say)
+	e.EncodeRecord(Record{Message: rcon, Server: "10.0.0.1:27015", Line: 7})
+	e.EncodeRecord(Record{Line: 8, Err: errors.New("no match")})
+	e.Flush()
+
+	lines := strings.SplitAfter(buf.String(), "\n")
+	if len(lines) != 3 || lines[2] != "" {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+
+	if lines[0] != ToJSON(say) {
+		t.Errorf("Expected a message without origin to be written like ToJSON, got %q", lines[0])
+	}
+
+	wanted := strings.TrimSuffix(ToJSON(rcon), "}\n") + `,"origin":{"server":"10.0.0.1:27015","line":7}}` + "\n"
+	if lines[1] != wanted {
+		t.Errorf("Expected %q, got %q", wanted, lines[1])
+	}
+
+	// the origin doesn't get in the way of the fields of the message
+	if m, err := FromJSON([]byte(lines[1])); err != nil || m != rcon {
+		t.Errorf("Expected %+v, got %+v, %v", rcon, m, err)
+	}
+}
+
+func TestJSONLDecoder(t *testing.T) {
+	ti := time.Date(2025, 8, 31, 16, 30, 0, 0, time.UTC)
+	m := reloaded{NewMeta(ti, "Reloaded"), "ak47"}
+
+	input := strings.Join([]string{
+		strings.TrimSuffix(ToJSON(m), "\n"),
+		"",
+		`{"type":"Unheard"}`,
+		`{"type":`,
+	}, "\n")
+
+	d := NewJSONLDecoder(strings.NewReader(input))
+	var records []Record
+	for d.Scan() {
+		records = append(records, d.Record())
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+	if !errors.Is(records[0].Err, ErrorUnknownType) || records[0].Line != 1 {
+		t.Errorf("Expected ErrorUnknownType on line 1 without the custom type, got %+v", records[0])
+	}
+	if !errors.Is(records[1].Err, ErrorUnknownType) || records[1].Line != 3 || records[1].Raw != `{"type":"Unheard"}` {
+		t.Errorf("Expected ErrorUnknownType on line 3, got %+v", records[1])
+	}
+	if records[2].Err == nil || records[2].Message != nil || records[2].Line != 4 {
+		t.Errorf("Expected a syntax error on line 4, got %+v", records[2])
+	}
+
+	types := DefaultTypes()
+	types.Register("Reloaded", reloaded{})
+
+	d = NewJSONLDecoder(strings.NewReader(input), JSONLTypes(types))
+	if !d.Scan() || d.Message() != m {
+		t.Errorf("Expected %+v with the custom type, got %+v", m, d.Record())
+	}
+
+	buf := &bytes.Buffer{}
+	e := NewJSONLEncoder(buf, JSONLGzip())
+	e.Encode(m)
+	e.Close()
+
+	// a truncated gzip stream is a read error
+	d = NewJSONLDecoder(bytes.NewReader(buf.Bytes()[:buf.Len()-4]), JSONLTypes(types))
+	for d.Scan() {
+	}
+	if d.Err() == nil {
+		t.Error("Expected a read error for truncated gzip input")
+	}
+}
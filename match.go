@@ -0,0 +1,373 @@
+package cs2log
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MatchPhase is the phase a match is in
+type MatchPhase string
+
+// Phases of a match, the zero value means the phase isn't known yet
+const (
+	PhaseWarmup     MatchPhase = "warmup"
+	PhaseFreezeTime MatchPhase = "freezetime"
+	PhaseLive       MatchPhase = "live"
+	PhasePaused     MatchPhase = "paused"
+	PhaseHalftime   MatchPhase = "halftime"
+	PhaseOvertime   MatchPhase = "overtime"
+	PhaseGameOver   MatchPhase = "gameover"
+)
+
+// MatchOption configures a Match
+type MatchOption func(*Match)
+
+// MatchMaxRounds sets the number of rounds in regulation, the default is 24.
+// A "mp_maxrounds" cvar in the log overrides it.
+func MatchMaxRounds(n int) MatchOption {
+	return func(m *Match) {
+		m.maxRounds = n
+	}
+}
+
+// MatchOvertimeRounds sets the number of rounds of an overtime, the default
+// is 6. A "mp_overtime_maxrounds" cvar in the log overrides it.
+func MatchOvertimeRounds(n int) MatchOption {
+	return func(m *Match) {
+		m.overtimeRounds = n
+	}
+}
+
+// MatchSnapshot is the state of a match at one point in time
+type MatchSnapshot struct {
+	Phase MatchPhase `json:"phase"`
+	Map   string     `json:"map"`
+	// Round is the round being played, between rounds the one played last
+	Round        int `json:"round"`
+	RoundsPlayed int `json:"rounds_played"`
+	ScoreCT      int `json:"score_ct"`
+	ScoreT       int `json:"score_t"`
+	// TeamCT and TeamT are the names of the teams on each side
+	TeamCT string `json:"team_ct"`
+	TeamT  string `json:"team_t"`
+	// Players are the players on the server, sorted by side and name
+	Players []Player `json:"players"`
+	// Time is the time of the last message
+	Time time.Time `json:"time"`
+}
+
+// Match tracks phase, round, score, teams and roster of a match from its
+// messages. Feed every message in log order to Update, Snapshot can be
+// called at any time, also from other goroutines.
+//
+// Most of the messages the tracker needs are only known to the extended
+// patterns, so parse the log with ParseOrdered or a Parser.
+type Match struct {
+	mu sync.RWMutex
+
+	maxRounds      int
+	overtimeRounds int
+
+	phase MatchPhase
+	// resume is the phase to return to when a pause ends
+	resume       MatchPhase
+	mapName      string
+	round        int
+	roundsPlayed int
+	// roundLive is set from the end of the freeze time until the round ends
+	roundLive bool
+	// warmup is set from the start of the warmup until the match starts,
+	// rounds played meanwhile don't count
+	warmup  bool
+	scoreCT int
+	scoreT  int
+	teamCT  string
+	teamT   string
+	players map[string]Player
+	time    time.Time
+}
+
+// NewMatch returns a tracker for a match that hasn't started yet
+func NewMatch(opts ...MatchOption) *Match {
+	m := &Match{
+		maxRounds:      24,
+		overtimeRounds: 6,
+		players:        make(map[string]Player),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Update applies a message to the state of the match
+func (m *Match) Update(msg Message) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.time = msg.GetTime()
+	m.updateRoster(msg)
+
+	switch msg := msg.(type) {
+	case WorldGameCommencing, WarmupStart:
+		m.reset()
+		m.warmup = true
+		m.phase = PhaseWarmup
+
+	case WarmupEnd:
+		m.warmup = false
+
+	case WorldMatchStart:
+		m.reset()
+		m.warmup = false
+		m.mapName = msg.Map
+		m.round = 1
+		m.setPhase(PhaseFreezeTime)
+
+	case WorldRoundRestart:
+		m.reset()
+
+	case FreezTimeStart:
+		m.freezeStart()
+
+	case FreezePeriod:
+		if msg.Action == "start" {
+			m.freezeStart()
+		} else {
+			m.roundStart()
+		}
+
+	case WorldRoundStart:
+		m.roundStart()
+
+	case TeamNotice:
+		if !m.warmup {
+			m.scoreCT, m.scoreT = msg.ScoreCT, msg.ScoreT
+		}
+		m.roundEnd()
+
+	case WorldRoundEnd:
+		m.roundEnd()
+
+	case TeamScored:
+		if !m.warmup {
+			m.setScore(msg.Side, msg.Score)
+		}
+
+	case MatchStatus:
+		m.scoreCT, m.scoreT = msg.ScoreCT, msg.ScoreT
+		m.mapName = msg.Map
+		// the rounds played are -1 during warmup
+		if msg.RoundsPlayed >= 0 {
+			m.roundsPlayed = msg.RoundsPlayed
+		}
+
+	case TeamPlaying:
+		if msg.Side == "CT" {
+			m.teamCT = msg.TeamName
+		} else {
+			m.teamT = msg.TeamName
+		}
+
+	case MatchPause:
+		if msg.Action == "enabled" {
+			if m.phase != PhasePaused {
+				m.resume = m.phase
+				m.phase = PhasePaused
+			}
+		} else if m.phase == PhasePaused {
+			m.phase = m.resume
+		}
+
+	case ServerCvar:
+		m.updateCvar(msg.Name, msg.Value)
+
+	case GameOver:
+		m.gameOver(msg.Map, msg.ScoreCT, msg.ScoreT)
+
+	case GameOverDetailed:
+		m.gameOver(msg.Map, msg.ScoreCT, msg.ScoreT)
+	}
+}
+
+// Snapshot returns a copy of the current state
+func (m *Match) Snapshot() MatchSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s := MatchSnapshot{
+		Phase:        m.phase,
+		Map:          m.mapName,
+		Round:        m.round,
+		RoundsPlayed: m.roundsPlayed,
+		ScoreCT:      m.scoreCT,
+		ScoreT:       m.scoreT,
+		TeamCT:       m.teamCT,
+		TeamT:        m.teamT,
+		Players:      make([]Player, 0, len(m.players)),
+		Time:         m.time,
+	}
+
+	for _, p := range m.players {
+		s.Players = append(s.Players, p)
+	}
+
+	sort.Slice(s.Players, func(i, j int) bool {
+		if s.Players[i].Side != s.Players[j].Side {
+			return s.Players[i].Side < s.Players[j].Side
+		}
+		return s.Players[i].Name < s.Players[j].Name
+	})
+
+	return s
+}
+
+// reset clears round and score when a new game starts
+func (m *Match) reset() {
+	m.round = 0
+	m.roundsPlayed = 0
+	m.roundLive = false
+	m.scoreCT = 0
+	m.scoreT = 0
+}
+
+// setPhase changes the phase, during a pause the phase to resume to
+func (m *Match) setPhase(phase MatchPhase) {
+	if m.phase == PhasePaused {
+		m.resume = phase
+		return
+	}
+	m.phase = phase
+}
+
+func (m *Match) freezeStart() {
+	if m.warmup {
+		return
+	}
+
+	m.round = m.roundsPlayed + 1
+	m.roundLive = false
+	m.setPhase(PhaseFreezeTime)
+}
+
+func (m *Match) roundStart() {
+	if m.warmup || m.roundLive {
+		return
+	}
+
+	m.round = m.roundsPlayed + 1
+	m.roundLive = true
+
+	if m.round > m.maxRounds {
+		m.setPhase(PhaseOvertime)
+	} else {
+		m.setPhase(PhaseLive)
+	}
+}
+
+func (m *Match) roundEnd() {
+	if !m.roundLive {
+		return
+	}
+
+	m.roundLive = false
+	m.roundsPlayed++
+
+	if m.halftime() {
+		m.setPhase(PhaseHalftime)
+	}
+}
+
+// halftime reports whether the teams switch sides after the rounds played,
+// at the half of regulation and at the half of every overtime
+func (m *Match) halftime() bool {
+	if m.roundsPlayed <= m.maxRounds {
+		return m.roundsPlayed == m.maxRounds/2
+	}
+
+	if m.overtimeRounds < 2 {
+		return false
+	}
+
+	return (m.roundsPlayed-m.maxRounds)%m.overtimeRounds == m.overtimeRounds/2
+}
+
+// newHalf reports whether the next round starts a half, which the first
+// round, the round after halftime and the first round of every overtime do
+func (m *Match) newHalf() bool {
+	if m.roundsPlayed == 0 || m.halftime() {
+		return true
+	}
+
+	if m.roundsPlayed < m.maxRounds || m.overtimeRounds < 2 {
+		return false
+	}
+
+	return (m.roundsPlayed-m.maxRounds)%m.overtimeRounds == 0
+}
+
+func (m *Match) setScore(side string, score int) {
+	if side == "CT" {
+		m.scoreCT = score
+	} else {
+		m.scoreT = score
+	}
+}
+
+func (m *Match) gameOver(mapName string, scoreCT, scoreT int) {
+	m.mapName = mapName
+	m.scoreCT, m.scoreT = scoreCT, scoreT
+	m.roundLive = false
+	m.phase = PhaseGameOver
+}
+
+func (m *Match) updateCvar(name, value string) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return
+	}
+
+	switch name {
+	case "mp_maxrounds":
+		m.maxRounds = n
+	case "mp_overtime_maxrounds":
+		m.overtimeRounds = n
+	}
+}
+
+// updateRoster keeps names and sides of the players mentioned in msg
+func (m *Match) updateRoster(msg Message) {
+	switch msg := msg.(type) {
+	case PlayerDisconnected:
+		delete(m.players, playerKey(msg.Player))
+		return
+	case PlayerSwitched:
+		p := msg.Player
+		p.Side = msg.To
+		m.players[playerKey(p)] = p
+		return
+	case PlayerNameChanged:
+		delete(m.players, playerKey(msg.Player))
+		p := msg.Player
+		p.Name = msg.Name
+		m.players[playerKey(p)] = p
+		return
+	}
+
+	for _, p := range messagePlayers(msg) {
+		// accolades only carry the name
+		if p.SteamID == "" {
+			continue
+		}
+
+		key := playerKey(p)
+		if p.Side == "" {
+			p.Side = m.players[key].Side
+		}
+		m.players[key] = p
+	}
+}
@@ -0,0 +1,130 @@
+package cs2log
+
+import (
+	"strings"
+	"testing"
+)
+
+// parseLog parses a log with the extended patterns, failing on any error.
+// Lines without timestamp get 08/31/2025 - 16:30:00.000.
+func parseLog(t *testing.T, log string) []Message {
+	t.Helper()
+
+	var lines []string
+	for _, l := range strings.Split(strings.TrimSpace(log), "\n") {
+		l = strings.TrimSpace(l)
+		if _, _, ok := splitLogLine(l); !ok {
+			l = "08/31/2025 - 16:30:00.000: " + l
+		}
+		lines = append(lines, l)
+	}
+
+	messages, errs := ParseLinesOrdered(lines)
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected parse errors: %v", errs)
+	}
+
+	return messages
+}
+
+func TestMatch(t *testing.T) {
+	m := NewMatch(MatchMaxRounds(4), MatchOvertimeRounds(2))
+
+	steps := []struct {
+		log   string
+		phase MatchPhase
+		round int
+		ct, t int
+	}{
+		{`World triggered "Game_Commencing"
+			World triggered "Warmup_Start"
+			"Magixx<2><[U:1:111]><>" entered the game
+			"Magixx<2><[U:1:111]>" switched from team <Unassigned> to <CT>
+			"Kyle<3><BOT>" switched from team <Unassigned> to <TERRORIST>
+			"Zont1x<4><[U:1:222]>" switched from team <Unassigned> to <TERRORIST>
+			Team playing "CT": Alpha
+			Team playing "TERRORIST": Bravo`, PhaseWarmup, 0, 0, 0},
+		{`World triggered "Warmup_End"
+			Starting Freeze period
+			World triggered "Match_Start" on "de_dust2"`, PhaseFreezeTime, 1, 0, 0},
+		{`World triggered "Round_Start"`, PhaseLive, 1, 0, 0},
+		{`Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "0")
+			World triggered "Round_End"`, PhaseLive, 1, 1, 0},
+		{`Starting Freeze period
+			Match pause is enabled`, PhasePaused, 2, 1, 0},
+		{`Match unpaused`, PhaseFreezeTime, 2, 1, 0},
+		{`World triggered "Round_Start"
+			Team "TERRORIST" triggered "SFUI_Notice_Terrorists_Win" (CT "1") (T "1")
+			World triggered "Round_End"`, PhaseHalftime, 2, 1, 1},
+		{`Team playing "CT": Bravo
+			Team playing "TERRORIST": Alpha
+			Starting Freeze period`, PhaseFreezeTime, 3, 1, 1},
+		{`World triggered "Round_Start"
+			Team "CT" triggered "SFUI_Notice_Target_Saved" (CT "2") (T "1")
+			World triggered "Round_End"
+			Starting Freeze period
+			World triggered "Round_Start"
+			Team "TERRORIST" triggered "SFUI_Notice_Target_Bombed" (CT "2") (T "2")
+			MatchStatus: Score: 2:2 on map "de_dust2" RoundsPlayed: 4
+			World triggered "Round_End"
+			Starting Freeze period
+			World triggered "Round_Start"`, PhaseOvertime, 5, 2, 2},
+		{`Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "3") (T "2")
+			World triggered "Round_End"`, PhaseHalftime, 5, 3, 2},
+		{`Game Over: competitive mg_active de_dust2 score 3:2 after 40 min`, PhaseGameOver, 5, 3, 2},
+	}
+
+	for i, step := range steps {
+		for _, msg := range parseLog(t, step.log) {
+			m.Update(msg)
+		}
+
+		s := m.Snapshot()
+		if s.Phase != step.phase || s.Round != step.round || s.ScoreCT != step.ct || s.ScoreT != step.t {
+			t.Fatalf("step %d: expected %s round %d %d:%d, got %s round %d %d:%d",
+				i, step.phase, step.round, step.ct, step.t, s.Phase, s.Round, s.ScoreCT, s.ScoreT)
+		}
+	}
+
+	s := m.Snapshot()
+
+	if s.Map != "de_dust2" || s.RoundsPlayed != 5 {
+		t.Errorf("Expected 5 rounds on de_dust2, got %d on %s", s.RoundsPlayed, s.Map)
+	}
+
+	if s.TeamCT != "Bravo" || s.TeamT != "Alpha" {
+		t.Errorf("Expected Bravo on CT and Alpha on T, got %s and %s", s.TeamCT, s.TeamT)
+	}
+
+	var roster []string
+	for _, p := range s.Players {
+		roster = append(roster, p.Side+":"+p.Name)
+	}
+	if got := strings.Join(roster, " "); got != "CT:Magixx TERRORIST:Kyle TERRORIST:Zont1x" {
+		t.Errorf("Unexpected roster %s", got)
+	}
+}
+
+func TestMatch_Roster(t *testing.T) {
+	m := NewMatch()
+
+	for _, msg := range parseLog(t, `
+		"Magixx<2><[U:1:111]><>" entered the game
+		"Magixx<2><[U:1:111]>" switched from team <Unassigned> to <CT>
+		"Magixx<2><[U:1:111]><CT>" purchased "m4a1"
+		"Kyle<3><BOT><TERRORIST>" purchased "ak47"
+		"Bot<5><BOT><TERRORIST>" purchased "ak47"
+		"Bot<5><BOT><TERRORIST>" disconnected (reason "Kicked by Console")
+		"Magixx<2><[U:1:111]>" switched from team <CT> to <Spectator>`) {
+		m.Update(msg)
+	}
+
+	s := m.Snapshot()
+	if len(s.Players) != 2 {
+		t.Fatalf("Expected 2 players, got %+v", s.Players)
+	}
+
+	if p := s.Players[0]; p.Name != "Magixx" || p.Side != "Spectator" {
+		t.Errorf("Expected Magixx as spectator, got %+v", p)
+	}
+}
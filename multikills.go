@@ -0,0 +1,182 @@
+package cs2log
+
+import "time"
+
+// MultiKill is a round in which a player killed two or more enemies
+type MultiKill struct {
+	Round  int    `json:"round"`
+	Player Player `json:"player"`
+	Kills  int    `json:"kills"`
+	// Weapons are the weapons of the kills in order, one per kill
+	Weapons []string `json:"weapons"`
+	// First and Last are the times of the first and the last kill
+	First time.Time `json:"first"`
+	Last  time.Time `json:"last"`
+	// Span is the time from the first to the last kill
+	Span time.Duration `json:"span"`
+}
+
+// Ace reports whether the player killed the whole enemy team
+func (m MultiKill) Ace() bool {
+	return m.Kills >= 5
+}
+
+// PlayerMultiKills counts the multi-kill rounds of a player
+type PlayerMultiKills struct {
+	Player  Player `json:"player"`
+	Doubles int    `json:"2k"`
+	Triples int    `json:"3k"`
+	Quads   int    `json:"4k"`
+	// Aces are rounds with five or more kills
+	Aces int `json:"5k"`
+}
+
+// MultiKillReport holds the multi-kills of a match
+type MultiKillReport struct {
+	// Rounds are the multi-kills in the order of the rounds and first kills
+	Rounds []MultiKill `json:"rounds"`
+	// Players is keyed like the Scoreboard and holds every player
+	// that killed or was killed, also without multi-kills
+	Players map[string]PlayerMultiKills `json:"players"`
+}
+
+// AnalyzeMultiKills finds the 2k, 3k, 4k and aces of every round from the
+// kills of enemies, see SplitRounds
+func AnalyzeMultiKills(rounds []Round) MultiKillReport {
+	report := MultiKillReport{Players: make(map[string]PlayerMultiKills)}
+
+	for _, r := range rounds {
+		var sprees []*MultiKill
+		byPlayer := make(map[string]*MultiKill)
+
+		for _, k := range roundKills(r) {
+			for _, p := range []Player{k.Attacker, k.Victim} {
+				pm := report.Players[playerKey(p)]
+				pm.Player = p
+				report.Players[playerKey(p)] = pm
+			}
+
+			key := playerKey(k.Attacker)
+			m, ok := byPlayer[key]
+			if !ok {
+				m = &MultiKill{Round: r.Number, First: k.Time}
+				byPlayer[key] = m
+				sprees = append(sprees, m)
+			}
+
+			m.Player = k.Attacker
+			m.Kills++
+			m.Weapons = append(m.Weapons, k.Weapon)
+			m.Last = k.Time
+			m.Span = m.Last.Sub(m.First)
+		}
+
+		for _, m := range sprees {
+			if m.Kills < 2 {
+				continue
+			}
+
+			report.Rounds = append(report.Rounds, *m)
+
+			key := playerKey(m.Player)
+			pm := report.Players[key]
+			switch min(m.Kills, 5) {
+			case 2:
+				pm.Doubles++
+			case 3:
+				pm.Triples++
+			case 4:
+				pm.Quads++
+			case 5:
+				pm.Aces++
+			}
+			report.Players[key] = pm
+		}
+	}
+
+	return report
+}
+
+// Compare cross-checks the 3k, 4k and aces against the JSON statistics
+// logged at the end of the last round and returns the counts that differ.
+// Players are matched by account ID, bots and players missing on either
+// side are skipped.
+func (r MultiKillReport) Compare(stats JSONStatistics) []ScoreMismatch {
+	byAccount := make(map[int]PlayerMultiKills)
+	for _, pm := range r.Players {
+		if id, ok := accountID(pm.Player.SteamID); ok {
+			byAccount[id] = pm
+		}
+	}
+
+	var mismatches []ScoreMismatch
+	for _, ps := range stats.Players {
+		pm, ok := byAccount[ps.AccountID]
+		if !ok || ps.AccountID == 0 {
+			continue
+		}
+
+		fields := []struct {
+			name              string
+			derived, reported int
+		}{
+			{"triple_kills", pm.Triples, ps.TripleKills},
+			{"quad_kills", pm.Quads, ps.QuadKills},
+			{"ace_kills", pm.Aces, ps.AceKills},
+		}
+
+		for _, f := range fields {
+			if f.derived != f.reported {
+				mismatches = append(mismatches, ScoreMismatch{pm.Player, f.name, f.derived, f.reported})
+			}
+		}
+	}
+
+	sortMismatches(mismatches)
+
+	return mismatches
+}
+
+// CompareAccolades cross-checks the 3k, 4k and aces against the final
+// "3k", "4k" and "5k" accolades and returns the counts that differ.
+// Accolades only name the player, so players are matched by name.
+// The server only awards the leader of each category, players without
+// an accolade are skipped.
+func (r MultiKillReport) CompareAccolades(accolades []PlayerAccolade) []ScoreMismatch {
+	byName := make(map[string]PlayerMultiKills)
+	for _, pm := range r.Players {
+		byName[pm.Player.Name] = pm
+	}
+
+	var mismatches []ScoreMismatch
+	for _, a := range accolades {
+		if !a.IsFinal {
+			continue
+		}
+
+		pm, ok := byName[a.Player.Name]
+		if !ok {
+			continue
+		}
+
+		var derived int
+		switch a.Type {
+		case "3k":
+			derived = pm.Triples
+		case "4k":
+			derived = pm.Quads
+		case "5k":
+			derived = pm.Aces
+		default:
+			continue
+		}
+
+		if reported := int(a.Value); derived != reported {
+			mismatches = append(mismatches, ScoreMismatch{pm.Player, a.Type, derived, reported})
+		}
+	}
+
+	sortMismatches(mismatches)
+
+	return mismatches
+}
@@ -0,0 +1,105 @@
+package cs2log
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeMultiKills(t *testing.T) {
+	bot := func(i int) string {
+		return fmt.Sprintf(`"Bot%d<%d><BOT><CT>"`, i, 10+i)
+	}
+
+	log := []string{
+		`World triggered "Match_Start" on "de_nuke"`,
+		`Starting Freeze period`,
+		`08/31/2025 - 16:30:00.000: World triggered "Round_Start"`,
+		`08/31/2025 - 16:30:10.000: ` + kill(jame, kyle, "awp", false),
+		`08/31/2025 - 16:30:15.000: ` + kill(jame, zont1x, "deagle", true),
+		`08/31/2025 - 16:30:15.000: Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "0")`,
+		`08/31/2025 - 16:30:15.000: World triggered "Round_End"`,
+		`Starting Freeze period`,
+		`08/31/2025 - 16:31:00.000: World triggered "Round_Start"`,
+		`08/31/2025 - 16:31:01.000: ` + kill(zont1x, kyle, "ak47", false),
+	}
+	for i := 1; i <= 5; i++ {
+		log = append(log, fmt.Sprintf(`08/31/2025 - 16:31:%02d.000: `, 10+i)+kill(zont1x, bot(i), "ak47", false))
+	}
+	log = append(log,
+		`08/31/2025 - 16:31:15.000: Team "TERRORIST" triggered "SFUI_Notice_Terrorists_Win" (CT "1") (T "1")`,
+		`08/31/2025 - 16:31:15.000: World triggered "Round_End"`,
+		`Starting Freeze period`,
+		`08/31/2025 - 16:32:00.000: World triggered "Round_Start"`,
+		`08/31/2025 - 16:32:10.000: `+kill(zont1x, bot(1), "ak47", false),
+		`08/31/2025 - 16:32:20.000: `+kill(zont1x, bot(2), "ak47", false),
+		`08/31/2025 - 16:32:30.000: `+kill(zont1x, bot(3), "glock", false),
+		`08/31/2025 - 16:32:30.000: `+kill(magixx, zont1x, "m4a1", false),
+		`08/31/2025 - 16:32:30.000: Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "2") (T "1")`,
+		`08/31/2025 - 16:32:30.000: World triggered "Round_End"`,
+	)
+
+	report := AnalyzeMultiKills(SplitRounds(parseLog(t, strings.Join(log, "\n"))))
+
+	expected := []struct {
+		round   int
+		player  string
+		kills   int
+		weapons string
+		span    time.Duration
+	}{
+		{1, "Jame", 2, "awp,deagle", 5 * time.Second},
+		{2, "Zont1x", 5, "ak47,ak47,ak47,ak47,ak47", 4 * time.Second},
+		{3, "Zont1x", 3, "ak47,ak47,glock", 20 * time.Second},
+	}
+
+	if len(report.Rounds) != len(expected) {
+		t.Fatalf("Expected %d multi-kills, got %+v", len(expected), report.Rounds)
+	}
+
+	for i, e := range expected {
+		m := report.Rounds[i]
+		if m.Round != e.round || m.Player.Name != e.player || m.Kills != e.kills || strings.Join(m.Weapons, ",") != e.weapons || m.Span != e.span {
+			t.Errorf("Multi-kill %d: expected %+v, got %+v", i, e, m)
+		}
+	}
+
+	if !report.Rounds[1].Ace() || report.Rounds[2].Ace() {
+		t.Errorf("Expected only the second multi-kill to be an ace")
+	}
+
+	if z := report.Players["[U:1:222]"]; z.Triples != 1 || z.Aces != 1 || z.Doubles != 0 || z.Quads != 0 {
+		t.Errorf("Unexpected counts of Zont1x %+v", z)
+	}
+
+	if m, ok := report.Players["[U:1:111]"]; !ok || m != (PlayerMultiKills{Player: m.Player}) {
+		t.Errorf("Expected Magixx without multi-kills, got %+v", m)
+	}
+
+	stats := JSONStatistics{Players: map[string]PlayerStatistics{
+		"player_0": {AccountID: 222, TripleKills: 1, AceKills: 1},
+		"player_1": {AccountID: 333, TripleKills: 1},
+	}}
+	mismatches := report.Compare(stats)
+	if len(mismatches) != 1 || mismatches[0] != (ScoreMismatch{report.Players["[U:1:333]"].Player, "triple_kills", 0, 1}) {
+		t.Errorf("Expected only the triple kills of Jame to differ, got %+v", mismatches)
+	}
+
+	var accolades []PlayerAccolade
+	for _, l := range []string{
+		`ACCOLADE, FINAL: {5k},	Zont1x<4>,	VALUE: 1.000000,	POS: 1,	SCORE: 40.000000`,
+		`ACCOLADE, FINAL: {3k},	Jame<3>,	VALUE: 1.000000,	POS: 1,	SCORE: 20.000000`,
+		`ACCOLADE, ROUND: {3k},	Magixx<2>,	VALUE: 1.000000,	POS: 1,	SCORE: 20.000000`,
+		`ACCOLADE, FINAL: {mvp},	Zont1x<4>,	VALUE: 2.000000,	POS: 1,	SCORE: 20.000000`,
+	} {
+		for _, msg := range parseLog(t, l) {
+			accolades = append(accolades, msg.(PlayerAccolade))
+		}
+	}
+
+	mismatches = report.CompareAccolades(accolades)
+	if len(mismatches) != 1 || mismatches[0].Player.Name != "Jame" || mismatches[0].Field != "3k" || mismatches[0].Reported != 1 {
+		t.Errorf("Expected only the 3k accolade of Jame to differ, got %+v", mismatches)
+	}
+}
@@ -0,0 +1,191 @@
+package cs2log
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// sampleLines covers every pattern group with real-world shaped lines
+var sampleLines = []string{
+	`08/19/2025 - 15:12:44.000: server_message: "quit"`,
+	`08/19/2025 - 15:12:44.000: Starting Freeze period`,
+	`08/19/2025 - 15:12:44.000: World triggered "Match_Start" on "de_dust2"`,
+	`08/19/2025 - 15:12:44.000: World triggered "Round_Start"`,
+	`08/19/2025 - 15:12:44.000: World triggered "Restart_Round_(1_second)`,
+	`08/19/2025 - 15:12:44.000: World triggered "Round_End"`,
+	`08/19/2025 - 15:12:44.000: World triggered "Game_Commencing"`,
+	`08/19/2025 - 15:12:44.000: World triggered "Round_Freeze_End"`,
+	`08/19/2025 - 15:12:44.000: World triggered "Warmup_Start"`,
+	`08/19/2025 - 15:12:44.000: World triggered "Warmup_End"`,
+	`08/19/2025 - 15:12:44.000: World triggered "Something_New"`,
+	`08/19/2025 - 15:12:44.000: Team "CT" scored "1" with "5" players`,
+	`08/19/2025 - 15:12:44.000: Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "0")`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<12><[U:1:29384012]><>" connected, address "foo"`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<12><[U:1:29384012]><TERRORIST>" disconnected (reason "Disconnect")`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<12><[U:1:29384012]><>" entered the game`,
+	`08/19/2025 - 15:12:44.000: Banid: "Player-Name<12><[U:1:29384012]><>" was banned "for 15.00 minutes" by "Console"`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<12><[U:1:29384012]>" switched from team <TERRORIST> to <Spectator>`,
+	`08/19/2025 - 15:12:44.000: "Magixx<123><STEAM_1:0:123456><CT>" say ".pause"`,
+	`08/19/2025 - 15:12:44.000: "Magixx<123><STEAM_1:0:123456><CT>" say "nice shot"`,
+	`08/19/2025 - 15:12:44.000: "Magixx<123><STEAM_1:0:123456><CT>" say_team "rotate b"`,
+	`08/19/2025 - 15:12:44.000: "ragga<6><[U:1:109933575]><TERRORIST>" purchased "item_assaultsuit"`,
+	`08/19/2025 - 15:12:44.000: "Magixx<123><STEAM_1:0:123456><CT>" [1 2 3] killed "sh1ro<456><STEAM_1:0:654321><TERRORIST>" [4 5 6] with "ak47"`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<12><[U:1:29384012]><TERRORIST>" [-225 -1829 -168] killed "Zim<20><BOT><CT>" [-476 -1709 -110] with "glock" (headshot penetrated)`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<10><STEAM_1:1:0101010><CT>" assisted killing "Player-Name<12><[U:1:29384012]><TERRORIST>"`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<10><STEAM_1:1:0101010><CT>" flash-assisted killing "Player-Name<12><[U:1:29384012]><TERRORIST>"`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<2><[U:1:29384012]><TERRORIST>" [480 -67 1782] attacked "Jon<9><BOT><CT>" [-134 362 1613] with "ak47" (damage "27") (damage_armor "3") (health "73") (armor "96") (hitgroup "chest")`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<2><[U:1:29384012]><TERRORIST>" [480 -67 1782] was killed by the bomb.`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<2><[U:1:29384012]><TERRORIST>" [480 -67 1782] committed suicide with "hegrenade"`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<2><[U:1:29384012]><TERRORIST>" picked up "ump45"`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<2><[U:1:29384012]><TERRORIST>" dropped "knife"`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<2><[U:1:29384012]><TERRORIST>" money change 2050-1000 = $1050 (tracked) (purchase: item_assaultsuit)`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<2><[U:1:29384012]><TERRORIST>" money change 7700+300 = $8000`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<2><[U:1:29384012]><TERRORIST>" triggered "Got_The_Bomb"`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<2><[U:1:29384012]><TERRORIST>" triggered "Planted_The_Bomb"`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<2><[U:1:29384012]><TERRORIST>" triggered "Dropped_The_Bomb"`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<2><[U:1:29384012]><TERRORIST>" triggered "Bomb_Begin_Plant"`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<2><[U:1:29384012]><CT>" triggered "Begin_Bomb_Defuse_With_Kit"`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<2><[U:1:29384012]><CT>" triggered "Defused_The_Bomb"`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<12><[U:1:29384012]><TERRORIST>" threw flashbang [-716 -1636 -170] flashbang entindex 163)`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<12><[U:1:29384012]><TERRORIST>" threw smokegrenade [-716 -1636 -170]`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<12><[U:1:29384012]><TERRORIST>" blinded for 3.45 by "Player-Name<10><STEAM_1:1:0101010><CT>" from flashbang entindex 163`,
+	`08/19/2025 - 15:12:44.000: Molotov projectile spawned at -539.715820 -2332.986572 -100.142113, velocity -77.150497 824.855957 175.574585`,
+	`08/19/2025 - 15:12:44.000: Game Over: competitive mg_de_cache de_cache score 16:1 after 21 min`,
+	`08/19/2025 - 15:12:44.000: Game Over: competitive de_cache score 16:1 after 21 min`,
+	`08/19/2025 - 15:12:44.000: "Magixx<123><STEAM_1:0:123456><CT>" left buyzone with [ weapon_knife weapon_usp_silencer kevlar(100) weapon_awp ]`,
+	`08/19/2025 - 15:12:44.000: "sh1ro<456><STEAM_1:0:654321><>" STEAM USERID validated`,
+	`08/19/2025 - 15:12:44.000: ACCOLADE, FINAL: {3k}, sh1ro<456>, VALUE: 2.000000`,
+	`08/19/2025 - 15:12:44.000: MatchStatus: Score: 17:19 on map "de_dust2" RoundsPlayed: 36`,
+	`08/19/2025 - 15:12:44.000: MatchStatus: Team playing "TERRORIST": team_xHaPPy_`,
+	`08/19/2025 - 15:12:44.000: Team playing "CT": team_SHESKY`,
+	`08/19/2025 - 15:12:44.000: Match pause is enabled`,
+	`08/19/2025 - 15:12:44.000: Match pause is disabled`,
+	`08/19/2025 - 15:12:44.000: Match unpaused`,
+	`08/19/2025 - 15:12:44.000: "Magixx" sv_throw_molotov -1943.109 1620.291 94.267 -123.456 456.789 789.012`,
+	`08/19/2025 - 15:12:44.000: server_cvar: "mp_freezetime" "20"`,
+	`08/19/2025 - 15:12:44.000: "mp_maxrounds" = "24"`,
+	`08/19/2025 - 15:12:44.000: rcon from "192.168.1.100:12345": command "mp_pause_match 1"`,
+	`08/19/2025 - 15:12:44.000: Loading map "de_dust2"`,
+	`08/19/2025 - 15:12:44.000: Started map "de_dust2"`,
+	`08/19/2025 - 15:12:44.000: Log file started (file "logs/L000_000_000_000_0_202508191512_000.log")`,
+	`08/19/2025 - 15:12:44.000: Log file closed`,
+	`08/19/2025 - 15:12:44.000: "map" : "de_dust2"`,
+	`08/19/2025 - 15:12:44.000: "player_0" : "208135644,2,10250,19,23,9"`,
+	`08/19/2025 - 15:12:44.000: "Player-Name<12><STEAM_1:1:0101010><CT>" [-854 396 -286] does FOO BAR BAZ`,
+	`L 08/19/2025 - 15:12:44.123: World triggered "Round_Start"`,
+	`08/19/2025 - 15:12:44.000: `,
+	`08/19/2025 - 15:12:44.000 World triggered "Round_Start"`,
+	`foo`,
+	``,
+}
+
+// parseOrderedLinear is the reference implementation ParseOrdered used to
+// have: compile all patterns for the line and try them one after another
+func parseOrderedLinear(line string) (Message, error) {
+	result := LogLinePattern.FindStringSubmatch(line)
+	if result == nil {
+		return nil, ErrorNoMatch
+	}
+
+	ti, err := time.Parse("01/02/2006 - 15:04:05.000", result[1])
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range GetOrderedPatterns() {
+		if matches := p.Pattern.FindStringSubmatch(result[2]); matches != nil {
+			return p.Handler(ti, matches), nil
+		}
+	}
+
+	return NewUnknown(ti, result[1:]), nil
+}
+
+func TestParseOrdered_MatchesLinearScan(t *testing.T) {
+	for _, line := range sampleLines {
+		want, wantErr := parseOrderedLinear(line)
+		have, haveErr := ParseOrdered(line)
+
+		if !reflect.DeepEqual(wantErr, haveErr) {
+			t.Errorf("error mismatch for %q: want %v, have %v", line, wantErr, haveErr)
+		}
+
+		if !reflect.DeepEqual(want, have) {
+			t.Errorf("message mismatch for %q:\n\twant: %#v\n\thave: %#v", line, want, have)
+		}
+	}
+}
+
+func TestRequiredLiteral(t *testing.T) {
+	tests := []struct {
+		pattern string
+		literal string
+	}{
+		{PlayerKillPattern, `] killed "`},
+		{PlayerSayPattern, `>" say`},
+		{TriggeredEventPattern, ` triggered "`},
+		{PlayerBombGotPattern, `>" triggered "Got_The_Bomb"`},
+		{PlayerMoneyChangePattern, `>" money change `},
+		{FreezTimeStartPattern, `Starting Freeze period`},
+		{`(a|b)c?`, ``},
+	}
+
+	for _, tt := range tests {
+		if have := requiredLiteral(tt.pattern); have != tt.literal {
+			t.Errorf("requiredLiteral(%q) = %q, want %q", tt.pattern, have, tt.literal)
+		}
+	}
+}
+
+func TestSplitLogLine(t *testing.T) {
+	for _, line := range sampleLines {
+		result := LogLinePattern.FindStringSubmatch(line)
+		timestamp, content, ok := splitLogLine(line)
+
+		if ok != (result != nil) {
+			t.Errorf("splitLogLine(%q) ok = %v, want %v", line, ok, result != nil)
+			continue
+		}
+
+		if ok && (timestamp != result[1] || content != result[2]) {
+			t.Errorf("splitLogLine(%q) = %q, %q, want %q, %q", line, timestamp, content, result[1], result[2])
+		}
+	}
+}
+
+func benchmarkLines(b *testing.B, parse func(string) (Message, error)) {
+	for i := 0; i < b.N; i++ {
+		for _, line := range sampleLines {
+			parse(line)
+		}
+	}
+}
+
+func BenchmarkParseOrderedLinear(b *testing.B) {
+	benchmarkLines(b, parseOrderedLinear)
+}
+
+func BenchmarkParseOrderedTable(b *testing.B) {
+	benchmarkLines(b, ParseOrdered)
+}
+
+func BenchmarkParseDefaultPatterns(b *testing.B) {
+	benchmarkLines(b, Parse)
+}
+
+func BenchmarkParseOrderedKill(b *testing.B) {
+	line := `08/19/2025 - 15:12:44.000: "Magixx<123><STEAM_1:0:123456><CT>" [1 2 3] killed "sh1ro<456><STEAM_1:0:654321><TERRORIST>" [4 5 6] with "ak47"`
+
+	for i := 0; i < b.N; i++ {
+		ParseOrdered(line)
+	}
+}
+
+func BenchmarkParseKill(b *testing.B) {
+	line := `08/19/2025 - 15:12:44.000: "Magixx<123><STEAM_1:0:123456><CT>" [1 2 3] killed "sh1ro<456><STEAM_1:0:654321><TERRORIST>" [4 5 6] with "ak47"`
+
+	for i := 0; i < b.N; i++ {
+		Parse(line)
+	}
+}
@@ -0,0 +1,179 @@
+package cs2log
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrorUnknown error when a valid log line matches no pattern
+// and the parser is configured with UnknownError
+var ErrorUnknown = errors.New("unknown message")
+
+// ErrorSkipped error when a valid log line matches no pattern and the parser
+// is configured with UnknownSkip. ParseLines, Scanner, Stream and the
+// receivers drop these lines without reporting an error.
+var ErrorSkipped = errors.New("skipped message")
+
+// UnknownPolicy decides what a Parser does with a valid log line
+// that matches none of its patterns
+type UnknownPolicy int
+
+const (
+	// UnknownKeep returns an Unknown message holding the raw text
+	UnknownKeep UnknownPolicy = iota
+	// UnknownSkip returns ErrorSkipped, so a skipped line can be told apart
+	// from a line of an incomplete JSON block
+	UnknownSkip
+	// UnknownError returns ErrorUnknown
+	UnknownError
+)
+
+// Parser parses log lines with its own set of compiled patterns.
+// A Parser is immutable once created and safe for concurrent use,
+// so one Parser can serve many goroutines and different servers can
+// be parsed with different Parsers in the same process.
+type Parser struct {
+	table    *patternTable
+	location *time.Location
+	unknown  UnknownPolicy
+	strict   bool
+}
+
+// Option configures a Parser
+type Option func(*Parser)
+
+// WithPatterns sets the patterns of the parser. Patterns are tried in
+// the order given, the first match wins. The slice is copied, later
+// changes to it don't affect the parser.
+func WithPatterns(patterns []OrderedPattern) Option {
+	return func(p *Parser) {
+		p.table = newPatternTable(patterns)
+	}
+}
+
+// WithRegistry sets the patterns of the parser to a snapshot of the registry,
+// later changes to the registry don't affect the parser
+func WithRegistry(r *PatternRegistry) Option {
+	return WithPatterns(r.Patterns())
+}
+
+// WithLocation sets the time zone the server writes its timestamps in,
+// the default is UTC
+func WithLocation(loc *time.Location) Option {
+	return func(p *Parser) {
+		p.location = loc
+	}
+}
+
+// WithUnknown sets the policy for lines no pattern matches,
+// the default is UnknownKeep
+func WithUnknown(policy UnknownPolicy) Option {
+	return func(p *Parser) {
+		p.unknown = policy
+	}
+}
+
+// WithStrict enables strict parsing. A strict parser requires the timestamp
+// at the beginning of a line (optionally preceded by the "L " marker of log
+// files) instead of searching for it, and reports JSON statistics blocks
+// that are not valid JSON as errors instead of returning them with only
+// RawJSON set.
+func WithStrict(strict bool) Option {
+	return func(p *Parser) {
+		p.strict = strict
+	}
+}
+
+// NewParser creates a parser. Without options it behaves like ParseOrdered:
+// all default and custom patterns in priority order, UTC timestamps and
+// Unknown messages for unmatched lines.
+func NewParser(opts ...Option) *Parser {
+	p := &Parser{
+		table:    orderedTable,
+		location: time.UTC,
+		unknown:  UnknownKeep,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.location == nil {
+		p.location = time.UTC
+	}
+
+	return p
+}
+
+// defaultParser is the parser behind ParseOrdered and the stateful functions
+var defaultParser = NewParser()
+
+// Parse parses a single log line and returns the message
+// or an error if the line is not a log line
+func (p *Parser) Parse(line string) (Message, error) {
+	timestamp, content, ok := p.split(line)
+
+	// if there is no timestamp, parsing failed, return error
+	if !ok {
+		return nil, ErrorNoMatch
+	}
+
+	ti, err := p.parseTime(timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	if m := p.table.match(ti, content); m != nil {
+		return m, nil
+	}
+
+	// valid log line but no pattern is defined for it
+	switch p.unknown {
+	case UnknownSkip:
+		return nil, ErrorSkipped
+	case UnknownError:
+		return nil, ErrorUnknown
+	}
+
+	return NewUnknown(ti, []string{timestamp, content}), nil
+}
+
+// ParseStateful parses a log line with state management for multi-line events,
+// see the package-level ParseStateful. A state must only be used by one
+// goroutine at a time.
+func (p *Parser) ParseStateful(line string, state *ParserState) (Message, error) {
+	return p.parseStateful(line, state, p.Parse)
+}
+
+// ParseLines parses multiple log lines including multi-line JSON blocks
+// and returns all parsed events
+func (p *Parser) ParseLines(lines []string) ([]Message, []error) {
+	return parseLines(lines, p.ParseStateful)
+}
+
+// split extracts timestamp and message of a log line
+func (p *Parser) split(line string) (string, string, bool) {
+	if !p.strict {
+		return splitLogLine(line)
+	}
+
+	const layout = "00/00/0000 - 00:00:00.000: "
+
+	line = strings.TrimPrefix(line, "L ")
+	if len(line) < len(layout) || !matchesLayout(line[:len(layout)], layout) {
+		return "", "", false
+	}
+
+	content := line[len(layout):]
+	if n := strings.IndexByte(content, '\n'); n >= 0 {
+		content = content[:n]
+	}
+
+	return line[:len(layout)-2], content, true
+}
+
+// parseTime parses a timestamp with milliseconds in the parser's location
+func (p *Parser) parseTime(timestamp string) (time.Time, error) {
+	return time.ParseInLocation("01/02/2006 - 15:04:05.000", timestamp, p.location)
+}
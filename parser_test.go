@@ -0,0 +1,175 @@
+package cs2log
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParser_Defaults(t *testing.T) {
+	p := NewParser()
+
+	for _, line := range sampleLines {
+		want, wantErr := ParseOrdered(line)
+		have, haveErr := p.Parse(line)
+
+		if wantErr != haveErr || (want != nil) != (have != nil) {
+			t.Fatalf("default parser differs from ParseOrdered for %q", line)
+		}
+
+		if want != nil && (want.GetType() != have.GetType() || !want.GetTime().Equal(have.GetTime())) {
+			t.Errorf("default parser differs from ParseOrdered for %q: %v vs %v", line, want, have)
+		}
+	}
+}
+
+func TestParser_WithPatterns(t *testing.T) {
+	p := NewParser(WithPatterns([]OrderedPattern{
+		{regexp.MustCompile(PlayerSayPattern), NewPlayerSay},
+	}))
+
+	msg, err := p.Parse(`08/19/2025 - 15:12:44.000: "Magixx<123><STEAM_1:0:123456><CT>" say ".pause"`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	if msg.GetType() != "PlayerSay" {
+		t.Errorf("Expected PlayerSay without ChatCommand pattern, got %s", msg.GetType())
+	}
+
+	msg, _ = p.Parse(`08/19/2025 - 15:12:44.000: World triggered "Round_Start"`)
+	if msg.GetType() != "Unknown" {
+		t.Errorf("Expected Unknown for pattern outside of set, got %s", msg.GetType())
+	}
+}
+
+func TestParser_WithLocation(t *testing.T) {
+	loc := time.FixedZone("CEST", 2*60*60)
+	p := NewParser(WithLocation(loc))
+
+	msg, err := p.Parse(`08/19/2025 - 15:12:44.250: World triggered "Round_Start"`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	want := time.Date(2025, time.August, 19, 13, 12, 44, 250*int(time.Millisecond), time.UTC)
+	if !msg.GetTime().Equal(want) {
+		t.Errorf("Expected %v, got %v", want, msg.GetTime().UTC())
+	}
+}
+
+func TestParser_WithUnknown(t *testing.T) {
+	line := `08/19/2025 - 15:12:44.000: something nobody knows`
+
+	msg, err := NewParser(WithUnknown(UnknownSkip)).Parse(line)
+	if msg != nil || err != ErrorSkipped {
+		t.Errorf("UnknownSkip: expected nil, ErrorSkipped, got %v, %v", msg, err)
+	}
+
+	// skipped lines are dropped without an error
+	messages, errs := NewParser(WithUnknown(UnknownSkip)).ParseLines([]string{line, `08/19/2025 - 15:12:44.000: Match unpaused`})
+	if len(messages) != 1 || len(errs) != 0 {
+		t.Errorf("UnknownSkip: expected 1 message and no errors, got %v, %v", messages, errs)
+	}
+
+	msg, err = NewParser(WithUnknown(UnknownError)).Parse(line)
+	if msg != nil || err != ErrorUnknown {
+		t.Errorf("UnknownError: expected nil, ErrorUnknown, got %v, %v", msg, err)
+	}
+
+	msg, err = NewParser(WithUnknown(UnknownKeep)).Parse(line)
+	if err != nil || msg.(Unknown).Raw != "something nobody knows" {
+		t.Errorf("UnknownKeep: expected Unknown message, got %v, %v", msg, err)
+	}
+}
+
+func TestParser_WithStrict(t *testing.T) {
+	p := NewParser(WithStrict(true))
+
+	tests := []struct {
+		line string
+		ok   bool
+	}{
+		{`08/19/2025 - 15:12:44.000: World triggered "Round_Start"`, true},
+		{`L 08/19/2025 - 15:12:44.000: World triggered "Round_Start"`, true},
+		{`garbage 08/19/2025 - 15:12:44.000: World triggered "Round_Start"`, false},
+		{`08/19/2025 - 15:12:44.000`, false},
+	}
+
+	for _, tt := range tests {
+		_, err := p.Parse(tt.line)
+		if (err == nil) != tt.ok {
+			t.Errorf("strict Parse(%q) error = %v, want ok %v", tt.line, err, tt.ok)
+		}
+
+		// the lenient default parser finds the timestamp anywhere
+		if _, err := NewParser().Parse(tt.line); tt.ok && err != nil {
+			t.Errorf("lenient Parse(%q) error = %v", tt.line, err)
+		}
+	}
+}
+
+func TestParser_StrictJSONBlock(t *testing.T) {
+	lines := []string{
+		`08/31/2025 - 16:30:18.000: JSON_BEGIN{`,
+		`08/31/2025 - 16:30:18.000: "name": "round_stats",`,
+		`08/31/2025 - 16:30:18.000: "round_number" : `,
+		`08/31/2025 - 16:30:18.000: }}JSON_END`,
+	}
+
+	messages, errs := NewParser().ParseLines(lines)
+	if len(errs) != 0 || len(messages) != 1 {
+		t.Fatalf("lenient: expected 1 message and no errors, got %d and %v", len(messages), errs)
+	}
+
+	messages, errs = NewParser(WithStrict(true)).ParseLines(lines)
+	if len(errs) != 1 || len(messages) != 0 {
+		t.Fatalf("strict: expected 1 error and no messages, got %d and %v", len(messages), errs)
+	}
+
+	if !strings.Contains(errs[0].Error(), "invalid JSON block") {
+		t.Errorf("unexpected error: %v", errs[0])
+	}
+}
+
+func TestParser_JSONBlockLocation(t *testing.T) {
+	loc := time.FixedZone("EST", -5*60*60)
+	lines := []string{
+		`08/31/2025 - 16:30:18.000: JSON_BEGIN{`,
+		`08/31/2025 - 16:30:18.000: "name": "round_stats",`,
+		`08/31/2025 - 16:30:18.000: "round_number" : "3"`,
+		`08/31/2025 - 16:30:18.000: }}JSON_END`,
+	}
+
+	messages, errs := NewParser(WithLocation(loc)).ParseLines(lines)
+	if len(errs) != 0 || len(messages) != 1 {
+		t.Fatalf("expected 1 message, got %d and %v", len(messages), errs)
+	}
+
+	want := time.Date(2025, time.August, 31, 21, 30, 18, 0, time.UTC)
+	if !messages[0].GetTime().Equal(want) {
+		t.Errorf("Expected %v, got %v", want, messages[0].GetTime().UTC())
+	}
+}
+
+func TestParser_Concurrent(t *testing.T) {
+	parsers := []*Parser{
+		NewParser(),
+		NewParser(WithUnknown(UnknownError), WithLocation(time.FixedZone("X", 3600))),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		p := parsers[i%len(parsers)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, line := range sampleLines {
+				p.Parse(line)
+			}
+		}()
+	}
+	wg.Wait()
+}
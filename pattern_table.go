@@ -0,0 +1,154 @@
+package cs2log
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"sync"
+	"time"
+)
+
+// patternTable is a compiled, ordered list of patterns. Every entry carries
+// a literal that any match of its regular expression must contain, so a
+// line is only handed to the regular expressions whose literal it contains.
+// The table is read-only after construction and safe for concurrent use.
+type patternTable struct {
+	entries []tableEntry
+}
+
+// tableEntry is a single pattern of a patternTable
+type tableEntry struct {
+	literal string
+	pattern *regexp.Regexp
+	handler MessageFunc
+}
+
+// newPatternTable builds a table from patterns, keeping their order
+func newPatternTable(patterns []OrderedPattern) *patternTable {
+	t := &patternTable{entries: make([]tableEntry, 0, len(patterns))}
+
+	for _, p := range patterns {
+		t.entries = append(t.entries, tableEntry{
+			literal: literalOf(p.Pattern),
+			pattern: p.Pattern,
+			handler: p.Handler,
+		})
+	}
+
+	return t
+}
+
+// match returns the message of the first pattern matching content,
+// or nil if no pattern matches
+func (t *patternTable) match(ti time.Time, content string) Message {
+	for i := range t.entries {
+		e := &t.entries[i]
+
+		// cheap prefilter, the regular expression can't match without the literal
+		if e.literal != "" && !strings.Contains(content, e.literal) {
+			continue
+		}
+
+		if r := e.pattern.FindStringSubmatch(content); r != nil {
+			return e.handler(ti, r)
+		}
+	}
+
+	return nil
+}
+
+// requiredLiteral returns the longest literal string every match of the
+// regular expression expr must contain, or "" if there is none
+func requiredLiteral(expr string) string {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return ""
+	}
+
+	best := ""
+	for _, lit := range requiredLiterals(re.Simplify()) {
+		if len(lit) > len(best) {
+			best = lit
+		}
+	}
+
+	return best
+}
+
+// requiredLiterals collects the literals that are part of every match of re
+func requiredLiterals(re *syntax.Regexp) []string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			return nil
+		}
+		return []string{string(re.Rune)}
+	case syntax.OpCapture, syntax.OpPlus:
+		return requiredLiterals(re.Sub[0])
+	case syntax.OpRepeat:
+		if re.Min > 0 {
+			return requiredLiterals(re.Sub[0])
+		}
+	case syntax.OpConcat:
+		var lits []string
+		for _, sub := range re.Sub {
+			lits = append(lits, requiredLiterals(sub)...)
+		}
+		return lits
+	}
+
+	return nil
+}
+
+// literalCache maps compiled expressions to their required literal
+var literalCache sync.Map
+
+// literalOf returns the required literal of a compiled expression,
+// computing it only once per expression
+func literalOf(re *regexp.Regexp) string {
+	if lit, ok := literalCache.Load(re); ok {
+		return lit.(string)
+	}
+
+	lit := requiredLiteral(re.String())
+	literalCache.Store(re, lit)
+	return lit
+}
+
+// splitLogLine extracts timestamp and message of a log line. It finds the
+// same leftmost match as LogLinePattern without running a regular expression.
+func splitLogLine(line string) (timestamp string, content string, ok bool) {
+	// "MM/DD/YYYY - HH:MM:SS.mmm: " is 27 bytes long
+	const layout = "00/00/0000 - 00:00:00.000: "
+
+	for i := 0; i+len(layout) <= len(line); i++ {
+		if !matchesLayout(line[i:i+len(layout)], layout) {
+			continue
+		}
+
+		content = line[i+len(layout):]
+		if n := strings.IndexByte(content, '\n'); n >= 0 {
+			content = content[:n]
+		}
+
+		return line[i : i+len(layout)-2], content, true
+	}
+
+	return "", "", false
+}
+
+// matchesLayout reports whether s has the shape of layout,
+// where a '0' in layout stands for any ASCII digit
+func matchesLayout(s, layout string) bool {
+	for i := 0; i < len(layout); i++ {
+		if layout[i] == '0' {
+			if s[i] < '0' || s[i] > '9' {
+				return false
+			}
+		} else if s[i] != layout[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,278 @@
+package cs2log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+)
+
+// LineError is a parse error of a single line or JSON block
+type LineError struct {
+	// Line is the 1-based line number, for JSON blocks the line of JSON_BEGIN
+	Line int
+	// Raw is the line that failed to parse
+	Raw string
+	Err error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// StreamOption configures Stream
+type StreamOption func(*streamConfig)
+
+type streamConfig struct {
+	parser  *Parser
+	workers int
+}
+
+// StreamParser parses lines with the parser instead of ParseStateful
+func StreamParser(p *Parser) StreamOption {
+	return func(c *streamConfig) {
+		c.parser = p
+	}
+}
+
+// StreamWorkers sets the number of goroutines matching lines against
+// patterns, the default is GOMAXPROCS
+func StreamWorkers(n int) StreamOption {
+	return func(c *streamConfig) {
+		c.workers = n
+	}
+}
+
+// errDeferred tells the stream that a line is parsed by a worker
+var errDeferred = errors.New("deferred")
+
+// streamJob is a line or JSON block in input order, result receives its outcome
+type streamJob struct {
+	line   int
+	raw    string
+	result chan streamResult
+}
+
+type streamResult struct {
+	msg Message
+	err error
+}
+
+// Stream parses the log read from r concurrently and sends the messages in
+// their original order. Lines are matched against the patterns by several
+// workers, while multi-line JSON blocks are assembled in order with a
+// ParserState, so they are never split or interleaved.
+//
+// Parse errors are sent as *LineError to the error channel, a read error or
+// the error of a cancelled context ends the stream. Errors the caller doesn't
+// receive right away are queued, so the channels may be drained one after the
+// other. Both channels are closed when the stream ends, callers must receive
+// from both until then or cancel the context. A blocked read on r is not interrupted by cancellation,
+// close the reader to stop it.
+func Stream(ctx context.Context, r io.Reader, opts ...StreamOption) (<-chan Message, <-chan error) {
+	cfg := streamConfig{workers: runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.workers < 1 {
+		cfg.workers = 1
+	}
+
+	// block assembly follows the package-level ParseStateful unless a parser is given
+	blocks, parse := defaultParser, Parse
+	if cfg.parser != nil {
+		blocks, parse = cfg.parser, cfg.parser.Parse
+	}
+
+	messages := make(chan Message)
+	errs := make(chan error, 1)
+	jobs := make(chan streamJob)
+	order := make(chan streamJob, cfg.workers*4)
+
+	for i := 0; i < cfg.workers; i++ {
+		go func() {
+			for job := range jobs {
+				msg, err := parse(job.raw)
+				job.result <- streamResult{msg, err}
+			}
+		}()
+	}
+
+	go streamSplit(ctx, r, blocks, jobs, order)
+	go streamCollect(ctx, order, messages, errs)
+
+	return messages, errs
+}
+
+// streamSplit reads lines, assembles JSON blocks and hands single lines to the workers
+func streamSplit(ctx context.Context, r io.Reader, blocks *Parser, jobs chan<- streamJob, order chan<- streamJob) {
+	defer close(order)
+	defer close(jobs)
+
+	lines := newLineReader(r)
+	state := NewParserState()
+	blockLine := 0
+
+	// emit queues a job in input order, jobs without outcome go to a worker
+	emit := func(job streamJob, res *streamResult) bool {
+		job.result = make(chan streamResult, 1)
+		if res != nil {
+			job.result <- *res
+		}
+
+		select {
+		case order <- job:
+		case <-ctx.Done():
+			return false
+		}
+
+		if res == nil {
+			select {
+			case jobs <- job:
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for {
+		line, err := lines.next()
+		if err != nil {
+			if state.InJSONBlock {
+				blockErr := &LineError{blockLine, "", errors.New("incomplete JSON block at end of input")}
+				if !emit(streamJob{}, &streamResult{err: blockErr}) {
+					return
+				}
+			}
+
+			if err != io.EOF {
+				emit(streamJob{}, &streamResult{err: err})
+			}
+
+			return
+		}
+
+		if line == "" && !state.InJSONBlock {
+			continue
+		}
+
+		inBlock := state.InJSONBlock
+		msg, err := blocks.parseStateful(line, state, func(string) (Message, error) {
+			return nil, errDeferred
+		})
+
+		if state.InJSONBlock && !inBlock {
+			blockLine = lines.line
+		}
+
+		job := streamJob{line: lines.line, raw: line}
+		if inBlock {
+			job.line = blockLine
+		}
+
+		var ok bool
+		switch {
+		case err == errDeferred:
+			ok = emit(job, nil)
+		case err != nil:
+			ok = emit(job, &streamResult{err: &LineError{job.line, line, err}})
+		case msg != nil:
+			ok = emit(job, &streamResult{msg: msg})
+		default:
+			// the line is part of a JSON block
+			ok = ctx.Err() == nil
+		}
+
+		if !ok {
+			return
+		}
+	}
+}
+
+// streamCollect waits for the jobs in input order and sends their outcome
+func streamCollect(ctx context.Context, order <-chan streamJob, messages chan<- Message, errs chan<- error) {
+	defer close(errs)
+
+	pending, ok := streamMessages(ctx, order, messages, errs)
+	if !ok {
+		sendErr(errs, ctx.Err())
+		return
+	}
+
+	for _, err := range pending {
+		select {
+		case errs <- err:
+		case <-ctx.Done():
+			sendErr(errs, ctx.Err())
+			return
+		}
+	}
+
+	if ctx.Err() != nil {
+		sendErr(errs, ctx.Err())
+	}
+}
+
+// streamMessages sends the messages of the jobs and the parse errors the
+// caller is ready for, it returns the errors still pending when the jobs are
+// done and false if the context was cancelled
+func streamMessages(ctx context.Context, order <-chan streamJob, messages chan<- Message, errs chan<- error) ([]error, bool) {
+	defer close(messages)
+
+	var pending []error
+	for job := range order {
+		var res streamResult
+
+		select {
+		case res = <-job.result:
+		case <-ctx.Done():
+			return nil, false
+		}
+
+		if res.err != nil && res.err != ErrorSkipped {
+			err := res.err
+			var lineErr *LineError
+			if job.raw != "" && !errors.As(err, &lineErr) {
+				err = &LineError{job.line, job.raw, err}
+			}
+			pending = append(pending, err)
+		}
+
+		// errors are sent while waiting for the caller, so it may drain the
+		// channels in any order
+		for res.msg != nil {
+			var out chan<- error
+			var next error
+			if len(pending) > 0 {
+				out, next = errs, pending[0]
+			}
+
+			select {
+			case messages <- res.msg:
+				res.msg = nil
+			case out <- next:
+				pending = pending[1:]
+			case <-ctx.Done():
+				return nil, false
+			}
+		}
+	}
+
+	return pending, true
+}
+
+// sendErr sends an error if the channel has room for it
+func sendErr(errs chan<- error, err error) {
+	select {
+	case errs <- err:
+	default:
+	}
+}
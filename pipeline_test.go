@@ -0,0 +1,202 @@
+package cs2log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// drain receives from both channels until they are closed
+func drain(messages <-chan Message, errs <-chan error) ([]Message, []error) {
+	var msgs []Message
+	var errors []error
+
+	for messages != nil || errs != nil {
+		select {
+		case m, ok := <-messages:
+			if !ok {
+				messages = nil
+				continue
+			}
+			msgs = append(msgs, m)
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			errors = append(errors, err)
+		}
+	}
+
+	return msgs, errors
+}
+
+func TestStream_PreservesOrder(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(&b, "08/31/2025 - 16:30:18.000: Team \"CT\" scored \"%d\" with \"5\" players\n", i)
+
+		if i == 250 {
+			b.WriteString(scannerLog)
+		}
+	}
+
+	messages, errs := drain(Stream(context.Background(), strings.NewReader(b.String()), StreamWorkers(8)))
+
+	// 500 scores, 3 messages and 1 error from scannerLog
+	if len(messages) != 503 {
+		t.Fatalf("Expected 503 messages, got %d", len(messages))
+	}
+
+	score := 0
+	for i, m := range messages {
+		if ts, ok := m.(TeamScored); ok {
+			if ts.Score != score {
+				t.Fatalf("message %d: expected score %d, got %d", i, score, ts.Score)
+			}
+			score++
+		}
+	}
+
+	if messages[252].GetType() != "JSONStatistics" {
+		t.Errorf("Expected JSON block between the scores, got %s", messages[252].GetType())
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+
+	var lineErr *LineError
+	if !errors.As(errs[0], &lineErr) || lineErr.Line != 261 || lineErr.Raw != "not a log line" || !errors.Is(errs[0], ErrorNoMatch) {
+		t.Errorf("Unexpected error: %#v", errs[0])
+	}
+}
+
+func TestStream_MatchesParseLines(t *testing.T) {
+	input := strings.Join(sampleLines, "\n")
+
+	want, _ := ParseLines(sampleLines)
+	have, _ := drain(Stream(context.Background(), strings.NewReader(input), StreamWorkers(4)))
+
+	if len(want) != len(have) {
+		t.Fatalf("Expected %d messages, got %d", len(want), len(have))
+	}
+
+	for i := range want {
+		if want[i].GetType() != have[i].GetType() {
+			t.Errorf("message %d: expected %s, got %s", i, want[i].GetType(), have[i].GetType())
+		}
+	}
+}
+
+func TestStream_Parser(t *testing.T) {
+	p := NewParser(WithUnknown(UnknownError))
+	input := "08/31/2025 - 16:30:19.000: Match unpaused\n08/31/2025 - 16:30:19.000: something unknown\n"
+
+	messages, errs := drain(Stream(context.Background(), strings.NewReader(input), StreamParser(p)))
+
+	if len(messages) != 1 || messages[0].GetType() != "MatchPause" {
+		t.Errorf("Expected MatchPause, got %v", messages)
+	}
+
+	if len(errs) != 1 || !errors.Is(errs[0], ErrorUnknown) {
+		t.Errorf("Expected ErrorUnknown, got %v", errs)
+	}
+}
+
+func TestStream_Skip(t *testing.T) {
+	p := NewParser(WithUnknown(UnknownSkip))
+	input := "08/31/2025 - 16:30:19.000: something unknown\n08/31/2025 - 16:30:19.000: Match unpaused\n"
+
+	messages, errs := drain(Stream(context.Background(), strings.NewReader(input), StreamParser(p)))
+
+	if len(messages) != 1 || messages[0].GetType() != "MatchPause" {
+		t.Errorf("Expected MatchPause, got %v", messages)
+	}
+
+	if len(errs) != 0 {
+		t.Errorf("Expected skipped lines to be dropped, got %v", errs)
+	}
+}
+
+func TestStream_ReadError(t *testing.T) {
+	readErr := errors.New("connection reset")
+	r := io.MultiReader(strings.NewReader(`08/31/2025 - 16:30:19.000: World triggered "Round_Start"`+"\n"), iotest.ErrReader(readErr))
+
+	messages, errs := drain(Stream(context.Background(), r))
+
+	if len(messages) != 1 {
+		t.Errorf("Expected 1 message, got %d", len(messages))
+	}
+
+	if len(errs) != 1 || errs[0] != readErr {
+		t.Errorf("Expected read error, got %v", errs)
+	}
+}
+
+func TestStream_DrainMessagesFirst(t *testing.T) {
+	input := strings.Join([]string{
+		`08/31/2025 - 16:30:19.000: World triggered "Round_Start"`,
+		"not a log line",
+		`08/31/2025 - 16:30:20.000: World triggered "Round_End"`,
+		"still not a log line",
+		"neither is this",
+		`08/31/2025 - 16:30:21.000: World triggered "Round_Start"`,
+	}, "\n")
+
+	messages, errs := Stream(context.Background(), strings.NewReader(input), StreamWorkers(2))
+
+	var msgs []Message
+	for m := range messages {
+		msgs = append(msgs, m)
+	}
+
+	var lines []int
+	for err := range errs {
+		var lineErr *LineError
+		if !errors.As(err, &lineErr) {
+			t.Fatalf("Expected *LineError, got %v", err)
+		}
+		lines = append(lines, lineErr.Line)
+	}
+
+	if len(msgs) != 3 {
+		t.Errorf("Expected 3 messages, got %d", len(msgs))
+	}
+
+	if fmt.Sprint(lines) != "[2 4 5]" {
+		t.Errorf("Expected errors on lines 2, 4 and 5, got %v", lines)
+	}
+}
+
+func TestStream_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// endless input
+	r, w := io.Pipe()
+	go func() {
+		for {
+			if _, err := io.WriteString(w, "08/31/2025 - 16:30:19.000: World triggered \"Round_Start\"\n"); err != nil {
+				return
+			}
+		}
+	}()
+	defer r.Close()
+
+	messages, errs := Stream(ctx, r, StreamWorkers(2))
+
+	for i := 0; i < 10; i++ {
+		<-messages
+	}
+	cancel()
+
+	_, rest := drain(messages, errs)
+
+	if len(rest) == 0 || rest[len(rest)-1] != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", rest)
+	}
+}
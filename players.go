@@ -0,0 +1,94 @@
+package cs2log
+
+// botSteamID is the SteamID the log shows for every bot
+const botSteamID = "BOT"
+
+// playerKey identifies a player across messages. Players are identified by
+// their SteamID3, so every format of the same account yields the same key.
+// Bots all share the SteamID "BOT" and are told apart by name, use an
+// IdentityResolver to tell bot instances with the same name apart.
+func playerKey(p Player) string {
+	if p.SteamID == botSteamID {
+		return botSteamID + ":" + p.Name
+	}
+	if id := p.Steam(); id.IsAccount() {
+		return id.SteamID3()
+	}
+	return p.SteamID
+}
+
+// messagePlayers returns the players a message is about, attacker first
+func messagePlayers(m Message) []Player {
+	switch m := m.(type) {
+	case PlayerConnected:
+		return []Player{m.Player}
+	case PlayerDisconnected:
+		return []Player{m.Player}
+	case PlayerEntered:
+		return []Player{m.Player}
+	case PlayerBanned:
+		return []Player{m.Player}
+	case PlayerSwitched:
+		return []Player{m.Player}
+	case PlayerSay:
+		return []Player{m.Player}
+	case PlayerPurchase:
+		return []Player{m.Player}
+	case PlayerKill:
+		return []Player{m.Attacker, m.Victim}
+	case PlayerKillAssist:
+		return []Player{m.Attacker, m.Victim}
+	case PlayerFlashAssist:
+		return []Player{m.Attacker, m.Victim}
+	case PlayerAttack:
+		return []Player{m.Attacker, m.Victim}
+	case PlayerKilledBomb:
+		return []Player{m.Player}
+	case PlayerKilledSuicide:
+		return []Player{m.Player}
+	case PlayerPickedUp:
+		return []Player{m.Player}
+	case PlayerDropped:
+		return []Player{m.Player}
+	case PlayerMoneyChange:
+		return []Player{m.Player}
+	case PlayerBombGot:
+		return []Player{m.Player}
+	case PlayerBombPlanted:
+		return []Player{m.Player}
+	case PlayerBombDropped:
+		return []Player{m.Player}
+	case PlayerBombBeginDefuse:
+		return []Player{m.Player}
+	case PlayerBombDefused:
+		return []Player{m.Player}
+	case PlayerThrew:
+		return []Player{m.Player}
+	case PlayerBlinded:
+		return []Player{m.Attacker, m.Victim}
+	case PlayerLeftBuyzone:
+		return []Player{m.Player}
+	case PlayerValidated:
+		return []Player{m.Player}
+	case PlayerNameChanged:
+		return []Player{m.Player}
+	case PlayerAccolade:
+		return []Player{m.Player}
+	case ChatCommand:
+		return []Player{m.Player}
+	case BombEvent:
+		return []Player{m.Player}
+	}
+
+	return nil
+}
+
+// accountID returns the account number of a SteamID in any format
+// ParseSteamID accepts, the number JSON statistics identify players by
+func accountID(steamID string) (int, bool) {
+	id, err := ParseSteamID(steamID)
+	if err != nil || !id.IsAccount() {
+		return 0, false
+	}
+	return id.AccountID(), true
+}
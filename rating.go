@@ -0,0 +1,290 @@
+package cs2log
+
+import "time"
+
+// RatingOption configures Rate
+type RatingOption func(*ratingConfig)
+
+type ratingConfig struct {
+	maxRounds      int
+	overtimeRounds int
+	tradeWindow    time.Duration
+}
+
+// RatingMaxRounds sets the number of rounds in regulation, which splits the
+// match into halves, the default is 24
+func RatingMaxRounds(n int) RatingOption {
+	return func(c *ratingConfig) {
+		c.maxRounds = n
+	}
+}
+
+// RatingOvertimeRounds sets the number of rounds of an overtime, the default is 6
+func RatingOvertimeRounds(n int) RatingOption {
+	return func(c *ratingConfig) {
+		c.overtimeRounds = n
+	}
+}
+
+// RatingTradeWindow sets how soon after a death the killer has to die for
+// the death to count as traded, the default is 5 seconds
+func RatingTradeWindow(d time.Duration) RatingOption {
+	return func(c *ratingConfig) {
+		c.tradeWindow = d
+	}
+}
+
+// PlayerRating holds the per-round statistics and ratings of a player.
+//
+// Rating1 follows the published HLTV Rating 1.0:
+//
+//	KillRating     = KPR / 0.679
+//	SurvivalRating = (Rounds - Deaths) / Rounds / 0.317
+//	RMKRating      = (1K + 4·2K + 9·3K + 16·4K + 25·5K) / Rounds / 1.277
+//	Rating1        = (KillRating + 0.7·SurvivalRating + RMKRating) / 2.7
+//
+// HLTV didn't publish Rating 2.0, Impact and Rating2 use the widely used
+// community approximations, KAST as a percentage:
+//
+//	Impact  = 2.13·KPR + 0.42·APR - 0.41
+//	Rating2 = 0.0073·KAST + 0.3591·KPR - 0.5329·DPR + 0.2372·Impact + 0.0032·ADR + 0.1587
+type PlayerRating struct {
+	Player  Player `json:"player"`
+	Rounds  int    `json:"rounds"`
+	Kills   int    `json:"kills"`
+	Deaths  int    `json:"deaths"`
+	Assists int    `json:"assists"`
+	// KASTRounds is the number of rounds with a kill, assist, survival or trade
+	KASTRounds int `json:"kast_rounds"`
+	// Damage is the health damage dealt to enemies, without overkill
+	Damage int `json:"damage"`
+	// MultiKills counts the rounds by kills, index 0 are rounds with one
+	// kill and index 4 rounds with five or more
+	MultiKills [5]int `json:"multi_kills"`
+
+	// KAST is the percentage of KAST rounds
+	KAST    float64 `json:"kast"`
+	KPR     float64 `json:"kpr"`
+	DPR     float64 `json:"dpr"`
+	APR     float64 `json:"apr"`
+	ADR     float64 `json:"adr"`
+	Impact  float64 `json:"impact"`
+	Rating1 float64 `json:"rating1"`
+	Rating2 float64 `json:"rating2"`
+}
+
+// RatingReport holds the ratings of a match, keyed like the Scoreboard
+type RatingReport struct {
+	Players map[string]PlayerRating `json:"players"`
+	// Halves holds the ratings of each half, index 0 and 1 are the halves of
+	// regulation, the halves of the overtimes follow
+	Halves []map[string]PlayerRating `json:"halves"`
+}
+
+// Rate computes KAST, impact, kills, deaths and assists per round and the
+// ratings of every player from the rounds of a match, see SplitRounds.
+// A player takes part in every round a message names them on a team in.
+// Assists in KAST include flash assists, APR doesn't.
+func Rate(rounds []Round, opts ...RatingOption) RatingReport {
+	cfg := ratingConfig{maxRounds: 24, overtimeRounds: 6, tradeWindow: 5 * time.Second}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	total := make(map[string]*PlayerRating)
+	var halves []map[string]*PlayerRating
+
+	for _, r := range rounds {
+		h := cfg.half(r.Number)
+		for len(halves) <= h {
+			halves = append(halves, make(map[string]*PlayerRating))
+		}
+
+		for key, line := range rateRound(r, cfg.tradeWindow) {
+			addRoundLine(total, key, line)
+			addRoundLine(halves[h], key, line)
+		}
+	}
+
+	report := RatingReport{
+		Players: finishRatings(total),
+		Halves:  make([]map[string]PlayerRating, len(halves)),
+	}
+	for i, half := range halves {
+		report.Halves[i] = finishRatings(half)
+	}
+
+	return report
+}
+
+// half returns the 0-based half a round is played in
+func (c ratingConfig) half(number int) int {
+	n := number - 1
+	if n < 0 || c.maxRounds < 2 {
+		return 0
+	}
+
+	if n < c.maxRounds {
+		return n / (c.maxRounds / 2)
+	}
+
+	if c.overtimeRounds < 2 {
+		return 2
+	}
+	return 2 + (n-c.maxRounds)/(c.overtimeRounds/2)
+}
+
+// roundLine is what a player did in a single round
+type roundLine struct {
+	player  Player
+	kills   int
+	deaths  int
+	assists int
+	kast    bool
+	damage  int
+}
+
+// rateRound returns the lines of all players taking part in a round
+func rateRound(r Round, tradeWindow time.Duration) map[string]*roundLine {
+	lines := make(map[string]*roundLine)
+	line := func(p Player) *roundLine {
+		key := playerKey(p)
+		l, ok := lines[key]
+		if !ok {
+			l = &roundLine{}
+			lines[key] = l
+		}
+		l.player = p
+		return l
+	}
+
+	health := make(healthTracker)
+
+	for _, msg := range r.Messages {
+		for _, p := range messagePlayers(msg) {
+			if p.SteamID != "" && (p.Side == "CT" || p.Side == "TERRORIST") {
+				line(p)
+			}
+		}
+
+		switch msg := msg.(type) {
+		case PlayerKill:
+			line(msg.Victim).deaths++
+			if msg.Attacker.Side != msg.Victim.Side {
+				line(msg.Attacker).kills++
+			}
+		case PlayerKilledBomb:
+			line(msg.Player).deaths++
+		case PlayerKilledSuicide:
+			line(msg.Player).deaths++
+		case PlayerKillAssist:
+			line(msg.Attacker).assists++
+			line(msg.Attacker).kast = true
+		case PlayerFlashAssist:
+			line(msg.Attacker).kast = true
+		case PlayerAttack:
+			damage := health.damage(msg)
+			if msg.Attacker.Side != msg.Victim.Side {
+				line(msg.Attacker).damage += damage
+			}
+		}
+	}
+
+	kills := roundKills(r)
+	for i := range tradedKills(kills, tradeWindow) {
+		line(kills[i].Victim).kast = true
+	}
+
+	for _, l := range lines {
+		if l.kills > 0 || l.deaths == 0 {
+			l.kast = true
+		}
+	}
+
+	return lines
+}
+
+// roundKills returns the kills of enemies in a round in log order
+func roundKills(r Round) []PlayerKill {
+	var kills []PlayerKill
+	for _, msg := range r.Messages {
+		if k, ok := msg.(PlayerKill); ok && k.Attacker.Side != k.Victim.Side {
+			kills = append(kills, k)
+		}
+	}
+	return kills
+}
+
+// tradedKills maps the indexes of the kills whose killer was killed by a
+// teammate of the victim within the window to the index of that kill
+func tradedKills(kills []PlayerKill, window time.Duration) map[int]int {
+	traded := make(map[int]int)
+
+	for i, k := range kills {
+		for j := i + 1; j < len(kills); j++ {
+			t := kills[j]
+			if t.Time.Sub(k.Time) > window {
+				break
+			}
+
+			if playerKey(t.Victim) == playerKey(k.Attacker) && t.Attacker.Side == k.Victim.Side {
+				traded[i] = j
+				break
+			}
+		}
+	}
+
+	return traded
+}
+
+func addRoundLine(ratings map[string]*PlayerRating, key string, l *roundLine) {
+	pr, ok := ratings[key]
+	if !ok {
+		pr = &PlayerRating{}
+		ratings[key] = pr
+	}
+
+	pr.Player = l.player
+	pr.Rounds++
+	pr.Kills += l.kills
+	pr.Deaths += l.deaths
+	pr.Assists += l.assists
+	pr.Damage += l.damage
+
+	if l.kast {
+		pr.KASTRounds++
+	}
+
+	if l.kills > 0 {
+		pr.MultiKills[min(l.kills, 5)-1]++
+	}
+}
+
+// finishRatings computes the ratings from the counts
+func finishRatings(ratings map[string]*PlayerRating) map[string]PlayerRating {
+	finished := make(map[string]PlayerRating, len(ratings))
+
+	for key, pr := range ratings {
+		rounds := float64(pr.Rounds)
+
+		pr.KAST = float64(pr.KASTRounds) * 100 / rounds
+		pr.KPR = float64(pr.Kills) / rounds
+		pr.DPR = float64(pr.Deaths) / rounds
+		pr.APR = float64(pr.Assists) / rounds
+		pr.ADR = float64(pr.Damage) / rounds
+		pr.Impact = 2.13*pr.KPR + 0.42*pr.APR - 0.41
+
+		survival := (rounds - float64(pr.Deaths)) / rounds
+		multi := 0.0
+		for i, n := range pr.MultiKills {
+			multi += float64((i+1)*(i+1)) * float64(n)
+		}
+
+		pr.Rating1 = (pr.KPR/0.679 + 0.7*survival/0.317 + multi/rounds/1.277) / 2.7
+		pr.Rating2 = 0.0073*pr.KAST + 0.3591*pr.KPR - 0.5329*pr.DPR + 0.2372*pr.Impact + 0.0032*pr.ADR + 0.1587
+
+		finished[key] = *pr
+	}
+
+	return finished
+}
@@ -0,0 +1,101 @@
+package cs2log
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ratingLog is two rounds of two against two on de_nuke
+var ratingLog = strings.Join([]string{
+	`World triggered "Match_Start" on "de_nuke"`,
+	`08/31/2025 - 16:29:45.000: Starting Freeze period`,
+	`08/31/2025 - 16:30:00.000: World triggered "Round_Start"`,
+	`08/31/2025 - 16:30:10.000: ` + kill(kyle, magixx, "ak47", false),
+	`08/31/2025 - 16:30:12.000: ` + kill(jame, kyle, "awp", false),
+	`08/31/2025 - 16:30:30.000: ` + kill(jame, zont1x, "awp", false),
+	`08/31/2025 - 16:30:30.000: Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "0")`,
+	`08/31/2025 - 16:30:30.000: World triggered "Round_End"`,
+	`08/31/2025 - 16:31:45.000: Starting Freeze period`,
+	`08/31/2025 - 16:31:50.000: ` + kyle + ` purchased "ak47"`,
+	`08/31/2025 - 16:32:00.000: World triggered "Round_Start"`,
+	`08/31/2025 - 16:32:10.000: ` + attack(zont1x, jame, "ak47", 120, 0),
+	`08/31/2025 - 16:32:10.000: ` + kill(zont1x, jame, "ak47", true),
+	`08/31/2025 - 16:32:20.000: ` + kill(magixx, zont1x, "m4a1", false),
+	`08/31/2025 - 16:32:20.000: ` + jame + ` flash-assisted killing ` + zont1x,
+	`08/31/2025 - 16:32:20.000: Team "TERRORIST" triggered "SFUI_Notice_Target_Bombed" (CT "1") (T "1")`,
+	`08/31/2025 - 16:32:20.000: World triggered "Round_End"`,
+}, "\n")
+
+func TestRate(t *testing.T) {
+	rounds := SplitRounds(parseLog(t, ratingLog))
+	report := Rate(rounds, RatingMaxRounds(2))
+
+	expected := map[string]struct {
+		kills, deaths, kast int
+	}{
+		"[U:1:111]": {1, 1, 2},
+		"[U:1:333]": {2, 1, 2},
+		"BOT:Kyle":  {1, 1, 2},
+		"[U:1:222]": {1, 2, 1},
+	}
+
+	if len(report.Players) != len(expected) {
+		t.Fatalf("Expected %d players, got %+v", len(expected), report.Players)
+	}
+
+	for key, e := range expected {
+		r := report.Players[key]
+		if r.Rounds != 2 || r.Kills != e.kills || r.Deaths != e.deaths || r.KASTRounds != e.kast {
+			t.Errorf("%s: expected 2 rounds %d-%d with %d KAST rounds, got %d rounds %d-%d with %d",
+				key, e.kills, e.deaths, e.kast, r.Rounds, r.Kills, r.Deaths, r.KASTRounds)
+		}
+	}
+
+	jame := report.Players["[U:1:333]"]
+	if jame.MultiKills != [5]int{0, 1, 0, 0, 0} {
+		t.Errorf("Expected a single 2k round, got %v", jame.MultiKills)
+	}
+
+	for name, v := range map[string][2]float64{
+		"KAST":    {jame.KAST, 100},
+		"KPR":     {jame.KPR, 1},
+		"DPR":     {jame.DPR, 0.5},
+		"Impact":  {jame.Impact, 1.72},
+		"Rating1": {jame.Rating1, 1.534454},
+		"Rating2": {jame.Rating2, 1.389334},
+	} {
+		if math.Abs(v[0]-v[1]) > 1e-6 {
+			t.Errorf("%s: expected %v, got %v", name, v[1], v[0])
+		}
+	}
+
+	if zont1x := report.Players["[U:1:222]"]; zont1x.ADR != 50 {
+		t.Errorf("Expected ADR 50 without overkill, got %v", zont1x.ADR)
+	}
+
+	if len(report.Halves) != 2 {
+		t.Fatalf("Expected 2 halves, got %d", len(report.Halves))
+	}
+
+	// Magixx died in the first half, traded by Jame within 2 seconds
+	if h := report.Halves[0]["[U:1:111]"]; h.Rounds != 1 || h.KASTRounds != 1 || h.Kills != 0 {
+		t.Errorf("Expected a traded death in the first half, got %+v", h)
+	}
+
+	// Jame's death in the second half was traded too late, the flash assist counts
+	if h := report.Halves[1]["[U:1:333]"]; h.Rounds != 1 || h.KASTRounds != 1 || h.Kills != 0 || h.Deaths != 1 {
+		t.Errorf("Expected a KAST round by flash assist in the second half, got %+v", h)
+	}
+}
+
+func TestRate_TradeWindow(t *testing.T) {
+	rounds := SplitRounds(parseLog(t, ratingLog))
+	report := Rate(rounds, RatingTradeWindow(time.Second))
+
+	// with a window of one second Magixx's death isn't traded anymore
+	if r := report.Players["[U:1:111]"]; r.KASTRounds != 1 {
+		t.Errorf("Expected 1 KAST round, got %d", r.KASTRounds)
+	}
+}
@@ -0,0 +1,89 @@
+package cs2log
+
+import "sync"
+
+// DefaultMaxServers is the number of servers a receiver keeps the parse state
+// of if it doesn't set a limit
+const DefaultMaxServers = 1024
+
+// serverStates keeps the parse state of every server a receiver gets logs from
+type serverStates struct {
+	mu      sync.Mutex
+	servers map[string]*serverState
+	clock   uint64
+}
+
+// serverState is the parse state of a single game server
+type serverState struct {
+	mu   sync.Mutex
+	asm  *assembler
+	line int
+	// used is the clock of serverStates when the server was last heard from
+	used uint64
+}
+
+// get returns the parse state of a server, creating it on first use. With
+// more than max servers the least recently used state is dropped, zero uses
+// DefaultMaxServers.
+func (ss *serverStates) get(server string, p *Parser, max int) *serverState {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.servers == nil {
+		ss.servers = make(map[string]*serverState)
+	}
+
+	if max <= 0 {
+		max = DefaultMaxServers
+	}
+
+	s, ok := ss.servers[server]
+	if !ok {
+		for len(ss.servers) >= max {
+			ss.evict()
+		}
+		s = &serverState{asm: newAssembler(statefulParse(p))}
+		ss.servers[server] = s
+	}
+
+	ss.clock++
+	s.used = ss.clock
+
+	return s
+}
+
+// evict drops the state of the server heard from least recently
+func (ss *serverStates) evict() {
+	var oldest string
+	used := ^uint64(0)
+	for server, s := range ss.servers {
+		if s.used < used {
+			oldest, used = server, s.used
+		}
+	}
+
+	delete(ss.servers, oldest)
+}
+
+// forget drops the parse state of a server
+func (ss *serverStates) forget(server string) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	delete(ss.servers, server)
+}
+
+// feed parses the lines in order and passes the completed records,
+// tagged with server, to handle
+func (s *serverState) feed(server string, lines []string, handle func(Record)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, line := range lines {
+		s.line++
+		if rec, ok := s.asm.feed(line, s.line); ok {
+			rec.Server = server
+			handle(rec)
+		}
+	}
+}
@@ -0,0 +1,469 @@
+package cs2log
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// Priorities of the built-in patterns. Patterns with a higher priority are
+// tried first, patterns with equal priority in the order they were added.
+const (
+	// PriorityDefault is the priority of all specific built-in patterns
+	PriorityDefault = 0
+	// PriorityFallback is the priority of catch-all patterns like TriggeredEvent,
+	// so patterns registered with PriorityDefault are still tried before them
+	PriorityFallback = -100
+)
+
+var (
+	// ErrorPatternExists error when a pattern name is already registered
+	ErrorPatternExists = errors.New("pattern already registered")
+	// ErrorPatternNotFound error when a pattern name is not registered
+	ErrorPatternNotFound = errors.New("pattern not registered")
+)
+
+// PatternEntry is a named pattern of a PatternRegistry
+type PatternEntry struct {
+	Name     string
+	Priority int
+	Pattern  *regexp.Regexp
+	Handler  MessageFunc
+}
+
+// PatternRegistry is an ordered set of named patterns. The order decides
+// which message a line produces when several patterns match it.
+// A registry is safe for concurrent use.
+type PatternRegistry struct {
+	mu      sync.RWMutex
+	entries []PatternEntry
+}
+
+// NewPatternRegistry creates an empty registry
+func NewPatternRegistry() *PatternRegistry {
+	return &PatternRegistry{}
+}
+
+// Register adds a pattern with the given priority. It is placed after all
+// patterns with a higher or equal priority and before those with a lower one.
+func (r *PatternRegistry) Register(name string, priority int, pattern *regexp.Regexp, handler MessageFunc) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.index(name) >= 0 {
+		return ErrorPatternExists
+	}
+
+	i := sort.Search(len(r.entries), func(i int) bool {
+		return r.entries[i].Priority < priority
+	})
+
+	r.insert(i, PatternEntry{name, priority, pattern, handler})
+	return nil
+}
+
+// InsertBefore adds a pattern directly before the pattern named target,
+// the new pattern takes over the priority of target
+func (r *PatternRegistry) InsertBefore(target, name string, pattern *regexp.Regexp, handler MessageFunc) error {
+	return r.insertAt(target, 0, name, pattern, handler)
+}
+
+// InsertAfter adds a pattern directly after the pattern named target,
+// the new pattern takes over the priority of target
+func (r *PatternRegistry) InsertAfter(target, name string, pattern *regexp.Regexp, handler MessageFunc) error {
+	return r.insertAt(target, 1, name, pattern, handler)
+}
+
+// Remove removes the pattern with the given name and
+// reports whether it was registered
+func (r *PatternRegistry) Remove(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(name)
+	if i < 0 {
+		return false
+	}
+
+	r.entries = append(r.entries[:i], r.entries[i+1:]...)
+	return true
+}
+
+// Lookup returns the pattern with the given name
+func (r *PatternRegistry) Lookup(name string) (PatternEntry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if i := r.index(name); i >= 0 {
+		return r.entries[i], true
+	}
+
+	return PatternEntry{}, false
+}
+
+// Entries returns a copy of all entries in priority order
+func (r *PatternRegistry) Entries() []PatternEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]PatternEntry(nil), r.entries...)
+}
+
+// Patterns returns all patterns in priority order
+func (r *PatternRegistry) Patterns() []OrderedPattern {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	patterns := make([]OrderedPattern, len(r.entries))
+	for i, e := range r.entries {
+		patterns[i] = OrderedPattern{e.Pattern, e.Handler}
+	}
+
+	return patterns
+}
+
+// Map returns all patterns as a map for ParseWithPatterns
+func (r *PatternRegistry) Map() map[*regexp.Regexp]MessageFunc {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	patterns := make(map[*regexp.Regexp]MessageFunc, len(r.entries))
+	for _, e := range r.entries {
+		patterns[e.Pattern] = e.Handler
+	}
+
+	return patterns
+}
+
+// Clone returns an independent copy of the registry
+func (r *PatternRegistry) Clone() *PatternRegistry {
+	return &PatternRegistry{entries: r.Entries()}
+}
+
+// index returns the position of the named entry or -1
+func (r *PatternRegistry) index(name string) int {
+	for i, e := range r.entries {
+		if e.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// insert places the entry at position i
+func (r *PatternRegistry) insert(i int, e PatternEntry) {
+	r.entries = append(r.entries, PatternEntry{})
+	copy(r.entries[i+1:], r.entries[i:])
+	r.entries[i] = e
+}
+
+// insertAt places a new entry at the position of target plus offset
+func (r *PatternRegistry) insertAt(target string, offset int, name string, pattern *regexp.Regexp, handler MessageFunc) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.index(name) >= 0 {
+		return ErrorPatternExists
+	}
+
+	i := r.index(target)
+	if i < 0 {
+		return ErrorPatternNotFound
+	}
+
+	r.insert(i+offset, PatternEntry{name, r.entries[i].Priority, pattern, handler})
+	return nil
+}
+
+// pattern sets a built-in pattern belongs to
+const (
+	inDefault = 1 << iota
+	inExtended
+	inOrdered
+)
+
+// builtinPattern describes a pattern of the built-in registries
+type builtinPattern struct {
+	name     string
+	expr     string
+	handler  MessageFunc
+	sets     int
+	priority int
+}
+
+// builtinPatterns lists all built-in patterns in priority order,
+// more specific patterns come before general patterns
+var builtinPatterns = []builtinPattern{
+	// Original specific patterns first (these should match before general ones)
+	{"ServerMessage", ServerMessagePattern, NewServerMessage, inDefault | inOrdered, PriorityDefault},
+	{"FreezTimeStart", FreezTimeStartPattern, NewFreezTimeStart, inDefault | inOrdered, PriorityDefault},
+	{"WorldMatchStart", WorldMatchStartPattern, NewWorldMatchStart, inDefault | inOrdered, PriorityDefault},
+	{"WorldRoundStart", WorldRoundStartPattern, NewWorldRoundStart, inDefault | inOrdered, PriorityDefault},
+	{"WorldRoundRestart", WorldRoundRestartPattern, NewWorldRoundRestart, inDefault | inOrdered, PriorityDefault},
+	{"WorldRoundEnd", WorldRoundEndPattern, NewWorldRoundEnd, inDefault | inOrdered, PriorityDefault},
+	{"WorldGameCommencing", WorldGameCommencingPattern, NewWorldGameCommencing, inDefault | inOrdered, PriorityDefault},
+	{"TeamScored", TeamScoredPattern, NewTeamScored, inDefault | inOrdered, PriorityDefault},
+	{"TeamNotice", TeamNoticePattern, NewTeamNotice, inDefault | inOrdered, PriorityDefault},
+	{"PlayerConnected", PlayerConnectedPattern, NewPlayerConnected, inDefault | inOrdered, PriorityDefault},
+	{"PlayerDisconnected", PlayerDisconnectedPattern, NewPlayerDisconnected, inDefault | inOrdered, PriorityDefault},
+	{"PlayerEntered", PlayerEnteredPattern, NewPlayerEntered, inDefault | inOrdered, PriorityDefault},
+	{"PlayerBanned", PlayerBannedPattern, NewPlayerBanned, inDefault | inOrdered, PriorityDefault},
+	{"PlayerSwitched", PlayerSwitchedPattern, NewPlayerSwitched, inDefault | inOrdered, PriorityDefault},
+
+	// Chat command MUST come before PlayerSay
+	{"ChatCommand", ChatCommandPattern, NewChatCommand, inExtended | inOrdered, PriorityDefault},
+
+	{"PlayerSay", PlayerSayPattern, NewPlayerSay, inDefault | inOrdered, PriorityDefault},
+	{"PlayerPurchase", PlayerPurchasePattern, NewPlayerPurchase, inDefault | inOrdered, PriorityDefault},
+	{"PlayerKill", PlayerKillPattern, NewPlayerKill, inDefault | inOrdered, PriorityDefault},
+	{"PlayerKillAssist", PlayerKillAssistPattern, NewPlayerKillAssist, inDefault | inOrdered, PriorityDefault},
+	{"PlayerFlashAssist", PlayerFlashAssistPattern, NewPlayerFlashAssist, inDefault | inOrdered, PriorityDefault},
+	{"PlayerAttack", PlayerAttackPattern, NewPlayerAttack, inDefault | inOrdered, PriorityDefault},
+	{"PlayerKilledBomb", PlayerKilledBombPattern, NewPlayerKilledBomb, inDefault | inOrdered, PriorityDefault},
+	{"PlayerKilledSuicide", PlayerKilledSuicidePattern, NewPlayerKilledSuicide, inDefault | inOrdered, PriorityDefault},
+	{"PlayerPickedUp", PlayerPickedUpPattern, NewPlayerPickedUp, inDefault | inOrdered, PriorityDefault},
+	{"PlayerDropped", PlayerDroppedPattern, NewPlayerDropped, inDefault | inOrdered, PriorityDefault},
+	{"PlayerMoneyChange", PlayerMoneyChangePattern, NewPlayerMoneyChange, inDefault | inOrdered, PriorityDefault},
+	{"PlayerBombGot", PlayerBombGotPattern, NewPlayerBombGot, inDefault | inOrdered, PriorityDefault},
+	{"PlayerBombPlanted", PlayerBombPlantedPattern, NewPlayerBombPlanted, inDefault | inOrdered, PriorityDefault},
+	{"PlayerBombDropped", PlayerBombDroppedPattern, NewPlayerBombDropped, inDefault | inOrdered, PriorityDefault},
+	{"PlayerBombBeginDefuse", PlayerBombBeginDefusePattern, NewPlayerBombBeginDefuse, inDefault | inOrdered, PriorityDefault},
+	{"PlayerBombDefused", PlayerBombDefusedPattern, NewPlayerBombDefused, inDefault | inOrdered, PriorityDefault},
+	{"PlayerThrew", PlayerThrewPattern, NewPlayerThrew, inDefault | inOrdered, PriorityDefault},
+	{"PlayerBlinded", PlayerBlindedPattern, NewPlayerBlinded, inDefault | inOrdered, PriorityDefault},
+	{"ProjectileSpawned", ProjectileSpawnedPattern, NewProjectileSpawned, inDefault | inOrdered, PriorityDefault},
+	{"GameOver", GameOverPattern, NewGameOver, inDefault | inOrdered, PriorityDefault},
+
+	// Custom specific patterns
+	{"PlayerLeftBuyzone", PlayerLeftBuyzonePattern, NewPlayerLeftBuyzone, inExtended | inOrdered, PriorityDefault},
+	{"PlayerValidated", PlayerValidatedPattern, NewPlayerValidated, inExtended | inOrdered, PriorityDefault},
+	{"PlayerNameChanged", PlayerNameChangedPattern, NewPlayerNameChanged, inExtended | inOrdered, PriorityDefault},
+	{"PlayerAccolade", PlayerAccoladePattern, NewPlayerAccolade, inExtended | inOrdered, PriorityDefault},
+	{"MatchStatusScore", MatchStatusScorePattern, NewMatchStatus, inExtended | inOrdered, PriorityDefault},
+	{"TeamPlaying", TeamPlayingPattern, NewTeamPlaying, inExtended | inOrdered, PriorityDefault},
+	{"MatchStatusTeam", MatchStatusTeamPattern, NewTeamPlaying, inExtended | inOrdered, PriorityDefault},
+	{"MatchPauseEnabled", MatchPauseEnabledPattern, NewMatchPauseEnabled, inExtended | inOrdered, PriorityDefault},
+	{"MatchPauseDisabled", MatchPauseDisabledPattern, NewMatchPauseDisabled, inExtended | inOrdered, PriorityDefault},
+	{"MatchUnpause", MatchUnpausePattern, NewMatchUnpause, inExtended | inOrdered, PriorityDefault},
+	{"GrenadeThrowDebug", GrenadeThrowDebugPattern, NewGrenadeThrowDebug, inExtended | inOrdered, PriorityDefault},
+	{"ServerCvar", ServerCvarPattern, NewServerCvar, inExtended | inOrdered, PriorityDefault},
+	{"MpCvar", MpCvarPattern, NewServerCvar, inExtended | inOrdered, PriorityDefault},
+	{"RconCommand", RconCommandPattern, NewRconCommand, inExtended | inOrdered, PriorityDefault},
+	{"LoadingMap", LoadingMapPattern, NewLoadingMap, inExtended | inOrdered, PriorityDefault},
+	{"StartedMap", StartedMapPattern, NewStartedMap, inExtended | inOrdered, PriorityDefault},
+	{"LogFileStarted", LogFileStartedPattern, NewLogFileStarted, inExtended | inOrdered, PriorityDefault},
+	{"LogFileClosed", LogFileClosedPattern, NewLogFileClosed, inExtended | inOrdered, PriorityDefault},
+	{"GameOverDetailed", GameOverDetailedPattern, NewGameOverDetailed, inExtended | inOrdered, PriorityDefault},
+	{"BombBeginPlant", BombBeginPlantPattern, NewBombBeginPlant, inExtended | inOrdered, PriorityDefault},
+	{"BombPlantedTrigger", BombPlantedTriggerPattern, NewBombBeginPlant, inExtended | inOrdered, PriorityDefault},
+	{"BombDefusedTrigger", BombDefusedTriggerPattern, NewBombBeginPlant, inExtended | inOrdered, PriorityDefault},
+	{"FreezePeriodStart", FreezePeriodStartPattern, NewFreezePeriodStart, inExtended | inOrdered, PriorityDefault},
+	{"FreezePeriodEnd", FreezePeriodEndPattern, NewFreezePeriodEnd, inExtended | inOrdered, PriorityDefault},
+
+	// JSON Round Stats markers (before individual field patterns)
+	{"JSONBegin", JSONBeginPattern, NewJSONBegin, inOrdered, PriorityDefault},
+	{"JSONEnd", JSONEndPattern, NewJSONEnd, inOrdered, PriorityDefault},
+	{"StatsJSONStart", StatsJSONStartPattern, NewStatsJSONStart, inExtended, PriorityDefault},
+	{"StatsJSONEnd", StatsJSONEndPattern, NewStatsJSONEnd, inExtended, PriorityDefault},
+	{"RoundStatsName", RoundStatsNamePattern, NewRoundStatsName, inOrdered, PriorityDefault},
+	{"RoundStatsRound", RoundStatsRoundPattern, NewRoundStatsMetadata, inOrdered, PriorityDefault},
+	{"RoundStatsScoreT", RoundStatsScoreTPattern, NewRoundStatsMetadata, inOrdered, PriorityDefault},
+	{"RoundStatsScoreCT", RoundStatsScoreCTPattern, NewRoundStatsMetadata, inOrdered, PriorityDefault},
+	{"RoundStatsMap", RoundStatsMapPattern, NewRoundStatsMetadata, inOrdered, PriorityDefault},
+	{"RoundStatsServer", RoundStatsServerPattern, NewRoundStatsMetadata, inOrdered, PriorityDefault},
+	{"RoundStatsPlayersStart", RoundStatsPlayersStartPattern, NewRoundStatsMetadata, inOrdered, PriorityDefault},
+
+	// Round Statistics individual lines
+	{"RoundStatsFields", RoundStatsFieldsPattern, NewRoundStatsFields, inExtended | inOrdered, PriorityDefault},
+	{"RoundStatsPlayer", RoundStatsPlayerPattern, NewRoundStatsPlayer, inExtended | inOrdered, PriorityDefault},
+
+	// Warmup Events (must come before general TriggeredEvent)
+	{"WarmupStart", WarmupStartPattern, NewWarmupStart, inExtended | inOrdered, PriorityDefault},
+	{"WarmupEnd", WarmupEndPattern, NewWarmupEnd, inExtended | inOrdered, PriorityDefault},
+
+	// TriggeredEvent MUST be last as it's very general
+	{"TriggeredEvent", TriggeredEventPattern, NewTriggeredEvent, inExtended | inOrdered, PriorityFallback},
+}
+
+// newBuiltinRegistry compiles all built-in patterns of a set into a new registry
+func newBuiltinRegistry(set int) *PatternRegistry {
+	r := NewPatternRegistry()
+
+	for _, b := range builtinPatterns {
+		if b.sets&set != 0 {
+			r.entries = append(r.entries, PatternEntry{b.name, b.priority, regexp.MustCompile(b.expr), b.handler})
+		}
+	}
+
+	return r
+}
+
+// DefaultRegistry returns a new registry with the patterns of DefaultPatterns
+func DefaultRegistry() *PatternRegistry {
+	return newBuiltinRegistry(inDefault)
+}
+
+// ExtendedRegistry returns a new registry with the patterns of ExtendedPatterns
+func ExtendedRegistry() *PatternRegistry {
+	return newBuiltinRegistry(inExtended)
+}
+
+// CombinedRegistry returns a new registry with default and custom patterns,
+// the patterns used by ParseOrdered
+func CombinedRegistry() *PatternRegistry {
+	return newBuiltinRegistry(inOrdered)
+}
+
+// rankKey identifies a pattern by its expression and handler
+type rankKey struct {
+	expr    string
+	handler uintptr
+}
+
+// builtinRanks holds the position of every built-in pattern,
+// builtinExprRanks the first position of every built-in expression
+var builtinRanks, builtinExprRanks = rankBuiltins()
+
+// rankBuiltins ranks the built-in patterns by their position. Ranks are
+// even, so unknown patterns can be ranked between specific and fallback ones.
+func rankBuiltins() (map[rankKey]int, map[string]int) {
+	ranks := map[rankKey]int{}
+	exprRanks := map[string]int{}
+
+	for i, b := range builtinPatterns {
+		key := rankKey{b.expr, funcPointer(b.handler)}
+		if _, ok := ranks[key]; !ok {
+			ranks[key] = 2 * i
+		}
+		if _, ok := exprRanks[b.expr]; !ok {
+			exprRanks[b.expr] = 2 * i
+		}
+	}
+
+	return ranks, exprRanks
+}
+
+// unknownRank is the rank of patterns that are not built in,
+// directly before the first fallback pattern
+var unknownRank = func() int {
+	for i, b := range builtinPatterns {
+		if b.priority <= PriorityFallback {
+			return 2*i - 1
+		}
+	}
+	return 2 * len(builtinPatterns)
+}()
+
+// orderPatterns sorts a pattern map by the priority of the built-in patterns.
+// Patterns not known to the built-in registries are tried after the specific
+// and before the fallback patterns, ordered by expression, so the same map
+// always yields the same order.
+func orderPatterns(patterns map[*regexp.Regexp]MessageFunc) []OrderedPattern {
+	type ranked struct {
+		OrderedPattern
+		rank    int
+		expr    string
+		handler uintptr
+	}
+
+	entries := make([]ranked, 0, len(patterns))
+	for re, fn := range patterns {
+		e := ranked{OrderedPattern{re, fn}, unknownRank, re.String(), funcPointer(fn)}
+
+		if rank, ok := builtinRanks[rankKey{e.expr, e.handler}]; ok {
+			e.rank = rank
+		} else if rank, ok := builtinExprRanks[e.expr]; ok {
+			e.rank = rank
+		}
+
+		entries = append(entries, e)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		if a.expr != b.expr {
+			return a.expr < b.expr
+		}
+		return a.handler < b.handler
+	})
+
+	ordered := make([]OrderedPattern, len(entries))
+	for i, e := range entries {
+		ordered[i] = e.OrderedPattern
+	}
+
+	return ordered
+}
+
+// patternOrder is the order of the patterns of a map computed once. The order
+// only depends on the patterns and the code of their handlers, so it is valid
+// as long as the map holds the same patterns with handlers of the same code.
+type patternOrder struct {
+	patterns map[*regexp.Regexp]MessageFunc
+	keys     []*regexp.Regexp
+	handlers []uintptr
+}
+
+func newPatternOrder(patterns map[*regexp.Regexp]MessageFunc) patternOrder {
+	o := patternOrder{patterns: patterns}
+	for _, p := range orderPatterns(patterns) {
+		o.keys = append(o.keys, p.Pattern)
+		o.handlers = append(o.handlers, funcPointer(p.Handler))
+	}
+	return o
+}
+
+// valid reports whether the order is the order of patterns
+func (o patternOrder) valid(patterns map[*regexp.Regexp]MessageFunc) bool {
+	if reflect.ValueOf(o.patterns).UnsafePointer() != reflect.ValueOf(patterns).UnsafePointer() || len(o.keys) != len(patterns) {
+		return false
+	}
+
+	for i, re := range o.keys {
+		fn, ok := patterns[re]
+		if !ok || funcPointer(fn) != o.handlers[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// builtinOrders hold the orders of DefaultPatterns and ExtendedPatterns
+var builtinOrders = []patternOrder{
+	newPatternOrder(DefaultPatterns),
+	newPatternOrder(ExtendedPatterns),
+}
+
+// patternKeys returns the patterns of a map in priority order, see
+// orderPatterns. The orders of the built-in maps are computed once and
+// again after the maps were changed.
+func patternKeys(patterns map[*regexp.Regexp]MessageFunc) []*regexp.Regexp {
+	for _, o := range builtinOrders {
+		if o.valid(patterns) {
+			return o.keys
+		}
+	}
+
+	ordered := orderPatterns(patterns)
+	keys := make([]*regexp.Regexp, len(ordered))
+	for i, p := range ordered {
+		keys[i] = p.Pattern
+	}
+	return keys
+}
+
+// funcPointer returns the code pointer of a handler, nil handlers return 0
+func funcPointer(fn MessageFunc) uintptr {
+	if fn == nil {
+		return 0
+	}
+
+	return reflect.ValueOf(fn).Pointer()
+}
@@ -0,0 +1,234 @@
+package cs2log
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func registryNames(r *PatternRegistry) []string {
+	var names []string
+	for _, e := range r.Entries() {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+func TestPatternRegistry_Register(t *testing.T) {
+	r := NewPatternRegistry()
+	re := regexp.MustCompile(`x`)
+
+	r.Register("a", PriorityDefault, re, NewUnknown)
+	r.Register("fallback", PriorityFallback, re, NewUnknown)
+	r.Register("b", PriorityDefault, re, NewUnknown)
+	r.Register("first", 10, re, NewUnknown)
+
+	want := []string{"first", "a", "b", "fallback"}
+	if have := registryNames(r); !reflect.DeepEqual(want, have) {
+		t.Errorf("Expected order %v, got %v", want, have)
+	}
+
+	if err := r.Register("a", 0, re, NewUnknown); err != ErrorPatternExists {
+		t.Errorf("Expected ErrorPatternExists, got %v", err)
+	}
+}
+
+func TestPatternRegistry_InsertBeforeAfter(t *testing.T) {
+	r := NewPatternRegistry()
+	re := regexp.MustCompile(`x`)
+
+	r.Register("a", PriorityDefault, re, NewUnknown)
+	r.Register("b", PriorityDefault, re, NewUnknown)
+
+	if err := r.InsertBefore("b", "before-b", re, NewUnknown); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.InsertAfter("a", "after-a", re, NewUnknown); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.InsertAfter("missing", "c", re, NewUnknown); err != ErrorPatternNotFound {
+		t.Errorf("Expected ErrorPatternNotFound, got %v", err)
+	}
+
+	want := []string{"a", "after-a", "before-b", "b"}
+	if have := registryNames(r); !reflect.DeepEqual(want, have) {
+		t.Errorf("Expected order %v, got %v", want, have)
+	}
+
+	if !r.Remove("after-a") || r.Remove("after-a") {
+		t.Error("Remove should report whether the pattern was registered")
+	}
+
+	if _, ok := r.Lookup("after-a"); ok {
+		t.Error("Removed pattern still registered")
+	}
+}
+
+func TestPatternRegistry_Clone(t *testing.T) {
+	r := CombinedRegistry()
+	c := r.Clone()
+	c.Remove("PlayerSay")
+
+	if _, ok := r.Lookup("PlayerSay"); !ok {
+		t.Error("Removing from a clone changed the original registry")
+	}
+}
+
+func TestBuiltinRegistries(t *testing.T) {
+	if n := len(DefaultRegistry().Entries()); n != len(DefaultPatterns) {
+		t.Errorf("DefaultRegistry has %d patterns, DefaultPatterns %d", n, len(DefaultPatterns))
+	}
+
+	if n := len(ExtendedRegistry().Entries()); n != len(ExtendedPatterns) {
+		t.Errorf("ExtendedRegistry has %d patterns, ExtendedPatterns %d", n, len(ExtendedPatterns))
+	}
+
+	entries := CombinedRegistry().Entries()
+	if last := entries[len(entries)-1]; last.Name != "TriggeredEvent" || last.Priority != PriorityFallback {
+		t.Errorf("Expected TriggeredEvent as last fallback pattern, got %s", last.Name)
+	}
+}
+
+func TestParseWithPatterns_Deterministic(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns map[*regexp.Regexp]MessageFunc
+		line     string
+		expected string
+	}{
+		{
+			name:     "ChatCommand before PlayerSay",
+			patterns: CombinedPatterns(),
+			line:     `08/19/2025 - 15:12:44.000: "Magixx<123><STEAM_1:0:123456><CT>" say ".pause"`,
+			expected: "ChatCommand",
+		},
+		{
+			name:     "PlayerBombPlanted before BombPlantedTrigger",
+			patterns: CombinedPatterns(),
+			line:     `08/19/2025 - 15:12:44.000: "Magixx<123><STEAM_1:0:123456><TERRORIST>" triggered "Planted_The_Bomb"`,
+			expected: "PlayerBombPlanted",
+		},
+		{
+			name:     "WarmupStart before TriggeredEvent",
+			patterns: ExtendedPatterns,
+			line:     `08/19/2025 - 15:12:44.000: World triggered "Warmup_Start"`,
+			expected: "WarmupStart",
+		},
+		{
+			name:     "BombPlantedTrigger before TriggeredEvent",
+			patterns: ExtendedPatterns,
+			line:     `08/19/2025 - 15:12:44.000: "Magixx<123><STEAM_1:0:123456><TERRORIST>" triggered "Planted_The_Bomb"`,
+			expected: "BombEvent",
+		},
+		{
+			name:     "FreezTimeStart before FreezePeriodStart",
+			patterns: CombinedPatterns(),
+			line:     `08/19/2025 - 15:12:44.000: Starting Freeze period`,
+			expected: "FreezTimeStart",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				msg, err := ParseWithPatterns(tt.line, tt.patterns)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if msg.GetType() != tt.expected {
+					t.Fatalf("run %d: expected %s, got %s", i, tt.expected, msg.GetType())
+				}
+			}
+		})
+	}
+}
+
+func TestParseWithPatterns_UnknownPatternsBeforeFallback(t *testing.T) {
+	custom := func(ti time.Time, r []string) Message {
+		return ServerMessage{Meta: NewMeta(ti, "ServerMessage"), Text: r[1]}
+	}
+
+	patterns := ExtendedRegistry().Map()
+	patterns[regexp.MustCompile(`World triggered "(Custom_\w+)"`)] = custom
+
+	for i := 0; i < 50; i++ {
+		msg, _ := ParseWithPatterns(`08/19/2025 - 15:12:44.000: World triggered "Custom_Event"`, patterns)
+		if msg.GetType() != "ServerMessage" {
+			t.Fatalf("run %d: expected custom pattern before TriggeredEvent, got %s", i, msg.GetType())
+		}
+	}
+}
+
+func TestParseWithPatterns_CachedOrder(t *testing.T) {
+	for _, patterns := range []map[*regexp.Regexp]MessageFunc{DefaultPatterns, ExtendedPatterns} {
+		cached, computed := patternKeys(patterns), orderPatterns(patterns)
+		if len(cached) != len(computed) {
+			t.Fatalf("Expected %d cached patterns, got %d", len(computed), len(cached))
+		}
+		for i := range computed {
+			if cached[i] != computed[i].Pattern {
+				t.Errorf("%d: expected %s, got %s", i, computed[i].Pattern, cached[i])
+			}
+		}
+	}
+}
+
+func TestParseWithPatterns_ChangedMap(t *testing.T) {
+	line := `08/19/2025 - 15:12:44.000: "Player<12><[U:1:29384012]><CT>" purchased "m4a1"`
+	say := func(ti time.Time, r []string) Message {
+		return ServerMessage{Meta: NewMeta(ti, "ServerMessage"), Text: r[1]}
+	}
+
+	var purchase *regexp.Regexp
+	for re := range DefaultPatterns {
+		if re.String() == PlayerPurchasePattern {
+			purchase = re
+		}
+	}
+	handler := DefaultPatterns[purchase]
+	defer func() { DefaultPatterns[purchase] = handler }()
+
+	// a replaced handler takes effect right away
+	DefaultPatterns[purchase] = say
+	if msg, _ := Parse(line); msg.GetType() != "ServerMessage" {
+		t.Errorf("Expected the replaced handler to be called, got %s", msg.GetType())
+	}
+
+	// so does a pattern replacing another one
+	delete(DefaultPatterns, purchase)
+	re := regexp.MustCompile(`"(.+)<\d+><.+><.*>" purchased`)
+	DefaultPatterns[re] = say
+	defer delete(DefaultPatterns, re)
+
+	if msg, _ := Parse(line); msg.GetType() != "ServerMessage" {
+		t.Errorf("Expected the added pattern to match, got %s", msg.GetType())
+	}
+
+	delete(DefaultPatterns, re)
+	DefaultPatterns[purchase] = handler
+	if msg, _ := Parse(line); msg.GetType() != "PlayerPurchase" {
+		t.Errorf("Expected PlayerPurchase after restoring the map, got %s", msg.GetType())
+	}
+}
+
+func TestParser_WithRegistry(t *testing.T) {
+	r := CombinedRegistry()
+	r.InsertBefore("PlayerSay", "Greeting", regexp.MustCompile(`"(.+)<\d+><.*><.*>" say "(hello)"`), func(ti time.Time, m []string) Message {
+		return ServerMessage{Meta: NewMeta(ti, "ServerMessage"), Text: m[2]}
+	})
+
+	p := NewParser(WithRegistry(r))
+
+	msg, _ := p.Parse(`08/19/2025 - 15:12:44.000: "Magixx<123><STEAM_1:0:123456><CT>" say "hello"`)
+	if msg.GetType() != "ServerMessage" {
+		t.Errorf("Expected registered pattern to win, got %s", msg.GetType())
+	}
+
+	// the default parser is not affected
+	msg, _ = ParseOrdered(`08/19/2025 - 15:12:44.000: "Magixx<123><STEAM_1:0:123456><CT>" say "hello"`)
+	if msg.GetType() != "PlayerSay" {
+		t.Errorf("Expected PlayerSay from ParseOrdered, got %s", msg.GetType())
+	}
+}
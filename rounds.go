@@ -0,0 +1,179 @@
+package cs2log
+
+import "time"
+
+// Round holds the messages of a single round
+type Round struct {
+	// Number is the 1-based number of the round in the match
+	Number int `json:"number"`
+	// FreezeStart is the start of the freeze time
+	FreezeStart time.Time `json:"freeze_start"`
+	// Start is the end of the freeze time, when the round went live
+	Start time.Time `json:"start"`
+	// End is the time of the WorldRoundEnd message
+	End time.Time `json:"end"`
+	// Duration is the time from Start to End
+	Duration time.Duration `json:"duration"`
+	// Winner is the side that won the round, "CT" or "TERRORIST"
+	Winner string `json:"winner"`
+	// Reason is the notice the round was won with, e.g. "SFUI_Notice_Bomb_Defused"
+	Reason  string `json:"reason"`
+	ScoreCT int    `json:"score_ct"`
+	ScoreT  int    `json:"score_t"`
+	// Messages are all messages from the freeze time through WorldRoundEnd
+	Messages []Message `json:"messages"`
+	// Stats is the JSON statistics block logged after the round, if any
+	Stats *JSONStatistics `json:"stats,omitempty"`
+}
+
+// RoundSegmenter cuts a stream of messages into rounds. Rounds played during
+// the warmup, rounds interrupted by a restart and rounds that ended before a
+// restart or the start of the match but didn't get their statistics yet are
+// discarded.
+type RoundSegmenter struct {
+	match *Match
+	// cur is the round being played
+	cur *Round
+	// ended is the round that ended and waits for its statistics
+	ended *Round
+}
+
+// NewRoundSegmenter returns a segmenter for a match of the given format
+func NewRoundSegmenter(opts ...MatchOption) *RoundSegmenter {
+	return &RoundSegmenter{match: NewMatch(opts...)}
+}
+
+// Update adds the next message and returns the round it completed, if any.
+// A round is complete with its statistics or when the next round starts.
+func (s *RoundSegmenter) Update(msg Message) (Round, bool) {
+	s.match.Update(msg)
+
+	switch msg := msg.(type) {
+	case FreezTimeStart:
+		return s.freezeStart(msg)
+
+	case FreezePeriod:
+		if msg.Action == "start" {
+			return s.freezeStart(msg)
+		}
+		s.roundStart(msg)
+
+	case WorldRoundStart:
+		s.roundStart(msg)
+
+	case WorldRoundRestart:
+		s.cur, s.ended = nil, nil
+		return Round{}, false
+
+	case WorldMatchStart:
+		// the freeze time may start right before the match, that round counts
+		s.ended = nil
+		if s.cur != nil && !s.cur.Start.IsZero() {
+			s.cur = nil
+			return Round{}, false
+		}
+
+	case TeamNotice:
+		if s.cur != nil {
+			s.cur.Winner = msg.Side
+			s.cur.Reason = msg.Notice
+			s.cur.ScoreCT, s.cur.ScoreT = msg.ScoreCT, msg.ScoreT
+		}
+
+	case WorldRoundEnd:
+		if s.cur != nil {
+			s.cur.Messages = append(s.cur.Messages, msg)
+			s.roundEnd(msg)
+		}
+		return Round{}, false
+
+	case JSONStatistics:
+		if s.ended != nil {
+			s.ended.Stats = &msg
+			return s.Flush()
+		}
+	}
+
+	if s.cur != nil {
+		s.cur.Messages = append(s.cur.Messages, msg)
+	}
+
+	return Round{}, false
+}
+
+// Flush returns the round that ended but didn't get its statistics yet,
+// call it at the end of the input
+func (s *RoundSegmenter) Flush() (Round, bool) {
+	if s.ended == nil {
+		return Round{}, false
+	}
+
+	r := *s.ended
+	s.ended = nil
+
+	return r, true
+}
+
+func (s *RoundSegmenter) freezeStart(msg Message) (Round, bool) {
+	r, ok := s.Flush()
+
+	// the freeze time may start before the warmup ends,
+	// so whether the round counts is decided when it ends
+	s.cur = &Round{FreezeStart: msg.GetTime()}
+	s.cur.Messages = append(s.cur.Messages, msg)
+
+	return r, ok
+}
+
+func (s *RoundSegmenter) roundStart(msg Message) {
+	if s.cur == nil {
+		// the log started during the freeze time
+		s.cur = &Round{FreezeStart: msg.GetTime()}
+	}
+
+	if s.cur.Start.IsZero() {
+		s.cur.Start = msg.GetTime()
+	}
+}
+
+func (s *RoundSegmenter) roundEnd(msg Message) {
+	r := s.cur
+	s.cur = nil
+
+	if s.match.warmup {
+		return
+	}
+
+	r.Number = s.match.round
+	r.End = msg.GetTime()
+	if r.Start.IsZero() {
+		r.Start = r.FreezeStart
+	}
+	r.Duration = r.End.Sub(r.Start)
+
+	s.ended = r
+}
+
+// SplitRounds cuts the messages of a log into rounds. Rounds played before
+// the game was restarted don't count and are left out.
+func SplitRounds(messages []Message, opts ...MatchOption) []Round {
+	s := NewRoundSegmenter(opts...)
+
+	var rounds []Round
+	for _, msg := range messages {
+		if r, ok := s.Update(msg); ok {
+			rounds = append(rounds, r)
+		}
+
+		switch msg.(type) {
+		case WorldRoundRestart, WorldMatchStart:
+			rounds = rounds[:0]
+		}
+	}
+
+	if r, ok := s.Flush(); ok {
+		rounds = append(rounds, r)
+	}
+
+	return rounds
+}
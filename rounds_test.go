@@ -0,0 +1,137 @@
+package cs2log
+
+import (
+	"testing"
+	"time"
+)
+
+const roundsLog = `
+World triggered "Game_Commencing"
+World triggered "Warmup_Start"
+Starting Freeze period
+World triggered "Round_Start"
+Team "CT" triggered "SFUI_Notice_Round_Draw" (CT "0") (T "0")
+World triggered "Round_End"
+World triggered "Warmup_End"
+08/31/2025 - 16:30:00.000: Starting Freeze period
+08/31/2025 - 16:30:00.000: World triggered "Match_Start" on "de_nuke"
+08/31/2025 - 16:30:20.000: World triggered "Round_Start"
+08/31/2025 - 16:30:50.000: "Magixx<2><[U:1:111]><CT>" [0 0 0] killed "Zont1x<4><[U:1:222]><TERRORIST>" [0 0 0] with "m4a1"
+08/31/2025 - 16:31:40.000: Team "TERRORIST" triggered "SFUI_Notice_Target_Bombed" (CT "0") (T "1")
+08/31/2025 - 16:31:40.000: World triggered "Round_End"
+08/31/2025 - 16:31:40.000: JSON_BEGIN{
+08/31/2025 - 16:31:40.000: "name": "round_stats",
+08/31/2025 - 16:31:40.000: "round_number" : "1",
+08/31/2025 - 16:31:40.000: "players" : {
+08/31/2025 - 16:31:40.000: }}JSON_END
+08/31/2025 - 16:31:47.000: Starting Freeze period
+08/31/2025 - 16:32:07.000: World triggered "Round_Start"
+08/31/2025 - 16:32:10.000: World triggered "Restart_Round_(1_second)"
+08/31/2025 - 16:32:11.000: Starting Freeze period
+08/31/2025 - 16:32:31.000: World triggered "Round_Start"
+08/31/2025 - 16:33:31.000: Team "CT" triggered "SFUI_Notice_Bomb_Defused" (CT "1") (T "0")
+08/31/2025 - 16:33:31.000: World triggered "Round_End"
+08/31/2025 - 16:33:38.000: Starting Freeze period
+08/31/2025 - 16:33:58.000: World triggered "Round_Start"
+08/31/2025 - 16:34:28.000: Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "2") (T "0")
+08/31/2025 - 16:34:28.000: World triggered "Round_End"
+08/31/2025 - 16:34:28.000: JSON_BEGIN{
+08/31/2025 - 16:34:28.000: "name": "round_stats",
+08/31/2025 - 16:34:28.000: "round_number" : "2",
+08/31/2025 - 16:34:28.000: "players" : {
+08/31/2025 - 16:34:28.000: }}JSON_END
+`
+
+func TestRoundSegmenter(t *testing.T) {
+	s := NewRoundSegmenter()
+
+	var rounds []Round
+	for _, msg := range parseLog(t, roundsLog) {
+		if r, ok := s.Update(msg); ok {
+			rounds = append(rounds, r)
+		}
+	}
+	if r, ok := s.Flush(); ok {
+		rounds = append(rounds, r)
+	}
+
+	expected := []struct {
+		number   int
+		winner   string
+		reason   string
+		duration time.Duration
+		messages int
+		stats    int
+	}{
+		{1, "TERRORIST", "SFUI_Notice_Target_Bombed", 80 * time.Second, 6, 1},
+		{1, "CT", "SFUI_Notice_Bomb_Defused", 60 * time.Second, 4, 0},
+		{2, "CT", "SFUI_Notice_CTs_Win", 30 * time.Second, 4, 2},
+	}
+
+	if len(rounds) != len(expected) {
+		t.Fatalf("Expected %d rounds, got %d", len(expected), len(rounds))
+	}
+
+	for i, e := range expected {
+		r := rounds[i]
+
+		if r.Number != e.number || r.Winner != e.winner || r.Reason != e.reason || r.Duration != e.duration {
+			t.Errorf("round %d: expected %d %s %s %s, got %d %s %s %s",
+				i, e.number, e.winner, e.reason, e.duration, r.Number, r.Winner, r.Reason, r.Duration)
+		}
+
+		if len(r.Messages) != e.messages {
+			t.Errorf("round %d: expected %d messages, got %d", i, e.messages, len(r.Messages))
+		}
+
+		if _, ok := r.Messages[0].(FreezTimeStart); !ok {
+			t.Errorf("round %d: expected to start with FreezTimeStart, got %T", i, r.Messages[0])
+		}
+
+		if _, ok := r.Messages[len(r.Messages)-1].(WorldRoundEnd); !ok {
+			t.Errorf("round %d: expected to end with WorldRoundEnd, got %T", i, r.Messages[len(r.Messages)-1])
+		}
+
+		if (r.Stats == nil && e.stats != 0) || (r.Stats != nil && r.Stats.RoundNumber != e.stats) {
+			t.Errorf("round %d: expected stats of round %d, got %+v", i, e.stats, r.Stats)
+		}
+	}
+}
+
+func TestSplitRounds(t *testing.T) {
+	rounds := SplitRounds(parseLog(t, roundsLog))
+
+	// the round before the restart is void
+	if len(rounds) != 2 || rounds[0].Reason != "SFUI_Notice_Bomb_Defused" || rounds[1].Number != 2 {
+		t.Fatalf("Expected the 2 rounds after the restart, got %+v", rounds)
+	}
+
+	if rounds[0].ScoreCT != 1 || rounds[1].ScoreCT != 2 {
+		t.Errorf("Unexpected scores %d and %d", rounds[0].ScoreCT, rounds[1].ScoreCT)
+	}
+}
+
+func TestSplitRounds_RestartAfterRoundEnd(t *testing.T) {
+	for _, restart := range []string{
+		`World triggered "Restart_Round_(1_second)"`,
+		`World triggered "Match_Start" on "de_nuke"`,
+	} {
+		rounds := SplitRounds(parseLog(t, `
+08/31/2025 - 16:30:00.000: World triggered "Match_Start" on "de_nuke"
+08/31/2025 - 16:30:00.000: Starting Freeze period
+08/31/2025 - 16:30:20.000: World triggered "Round_Start"
+08/31/2025 - 16:31:40.000: Team "TERRORIST" triggered "SFUI_Notice_Target_Bombed" (CT "0") (T "1")
+08/31/2025 - 16:31:40.000: World triggered "Round_End"
+08/31/2025 - 16:31:45.000: `+restart+`
+08/31/2025 - 16:31:46.000: Starting Freeze period
+08/31/2025 - 16:32:06.000: World triggered "Round_Start"
+08/31/2025 - 16:33:06.000: Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "0")
+08/31/2025 - 16:33:06.000: World triggered "Round_End"
+`))
+
+		// the round that ended before the restart never got its statistics
+		if len(rounds) != 1 || rounds[0].Reason != "SFUI_Notice_CTs_Win" {
+			t.Errorf("%s: expected only the round after the restart, got %+v", restart, rounds)
+		}
+	}
+}
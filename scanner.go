@@ -0,0 +1,209 @@
+package cs2log
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Record is a parsed message together with its position in the log
+type Record struct {
+	// Message is the parsed message, nil if Err is set
+	Message Message
+	// Line is the 1-based line number, for JSON blocks the line of JSON_BEGIN
+	Line int
+	// Raw is the line without line break, for JSON blocks all lines of the block
+	Raw string
+	// Err is set when the line could not be parsed
+	Err error
+	// Server identifies the game server that sent the line, if known
+	Server string
+	// File is the name of the log file the line was read from, if known
+	File string
+}
+
+// Scanner reads log lines from an io.Reader and parses them one at a time.
+// Multi-line JSON blocks are assembled with a ParserState and returned as a
+// single JSONStatistics record. Lines may be of any length, the memory used
+// only depends on the longest line and JSON block, not on the size of the log.
+//
+// Scanning stops at the end of the input or at the first read error. Lines
+// that can't be parsed don't stop the scanner, they are returned as records
+// with Err set. Empty lines are skipped.
+type Scanner struct {
+	lines *lineReader
+	asm   *assembler
+
+	record Record
+	err    error
+	done   bool
+}
+
+// NewScanner returns a scanner that parses lines with ParseStateful
+func NewScanner(r io.Reader) *Scanner {
+	return newScanner(r, ParseStateful)
+}
+
+// NewScanner returns a scanner that parses lines with the parser
+func (p *Parser) NewScanner(r io.Reader) *Scanner {
+	return newScanner(r, p.ParseStateful)
+}
+
+func newScanner(r io.Reader, parse func(string, *ParserState) (Message, error)) *Scanner {
+	return &Scanner{
+		lines: newLineReader(r),
+		asm:   newAssembler(parse),
+	}
+}
+
+// Scan advances to the next record, which is then available through Record.
+// It returns false when the input is exhausted or a read error occurred.
+func (s *Scanner) Scan() bool {
+	for !s.done {
+		line, err := s.lines.next()
+
+		if err != nil {
+			s.done = true
+			if err != io.EOF {
+				s.err = err
+			}
+
+			// a JSON block still open at the end of the input can't be completed
+			rec, ok := s.asm.flush()
+			s.record = rec
+			return ok
+		}
+
+		if rec, ok := s.asm.feed(line, s.lines.line); ok {
+			s.record = rec
+			return true
+		}
+	}
+
+	return false
+}
+
+// Record returns the most recent record read by Scan
+func (s *Scanner) Record() Record {
+	return s.record
+}
+
+// Message returns the message of the most recent record, nil if it has an error
+func (s *Scanner) Message() Message {
+	return s.record.Message
+}
+
+// Err returns the first read error, parse errors are reported by Record
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// lineReader reads lines of any length and counts them
+type lineReader struct {
+	r    *bufio.Reader
+	buf  []byte
+	line int
+}
+
+func newLineReader(r io.Reader) *lineReader {
+	return &lineReader{r: bufio.NewReader(r)}
+}
+
+// next returns the next line without its line break
+func (l *lineReader) next() (string, error) {
+	l.buf = l.buf[:0]
+
+	for {
+		chunk, err := l.r.ReadSlice('\n')
+		l.buf = append(l.buf, chunk...)
+
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+
+		if err != nil && (err != io.EOF || len(l.buf) == 0) {
+			return "", err
+		}
+
+		l.line++
+		line := strings.TrimSuffix(string(l.buf), "\n")
+		return strings.TrimSuffix(line, "\r"), nil
+	}
+}
+
+// assembler turns lines into records, JSON blocks are assembled
+// with a ParserState and their raw lines are kept for the record
+type assembler struct {
+	parse     func(string, *ParserState) (Message, error)
+	state     *ParserState
+	block     []string
+	blockLine int
+}
+
+func newAssembler(parse func(string, *ParserState) (Message, error)) *assembler {
+	return &assembler{parse: parse, state: NewParserState()}
+}
+
+// statefulParse returns the stateful parse function of p,
+// or ParseStateful if p is nil
+func statefulParse(p *Parser) func(string, *ParserState) (Message, error) {
+	if p == nil {
+		return ParseStateful
+	}
+	return p.ParseStateful
+}
+
+// feed parses line number n and reports whether it completed a record,
+// empty lines outside of JSON blocks are skipped
+func (a *assembler) feed(line string, n int) (Record, bool) {
+	if line == "" && !a.state.InJSONBlock {
+		return Record{}, false
+	}
+
+	inBlock := a.state.InJSONBlock
+	msg, err := a.parse(line, a.state)
+
+	switch {
+	case a.state.InJSONBlock:
+		// the line started or continued a JSON block
+		if !inBlock {
+			a.block = a.block[:0]
+			a.blockLine = n
+		}
+		a.block = append(a.block, line)
+		return Record{}, false
+
+	case inBlock:
+		// the line completed or interrupted a JSON block
+		return Record{
+			Message: msg,
+			Line:    a.blockLine,
+			Raw:     strings.Join(append(a.block, line), "\n"),
+			Err:     err,
+		}, true
+	}
+
+	if msg == nil && err == nil || err == ErrorSkipped {
+		return Record{}, false
+	}
+
+	return Record{Message: msg, Line: n, Raw: line, Err: err}, true
+}
+
+// flush returns an error record for a JSON block that is still open
+// and resets the state, it reports false if there is none
+func (a *assembler) flush() (Record, bool) {
+	if !a.state.InJSONBlock {
+		return Record{}, false
+	}
+
+	rec := Record{
+		Line: a.blockLine,
+		Raw:  strings.Join(a.block, "\n"),
+		Err:  errors.New("incomplete JSON block at end of input"),
+	}
+	a.state.Reset()
+
+	return rec, true
+}
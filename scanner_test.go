@@ -0,0 +1,149 @@
+package cs2log
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+const scannerLog = `08/31/2025 - 16:30:17.000: World triggered "Round_End"
+08/31/2025 - 16:30:18.000: JSON_BEGIN{
+08/31/2025 - 16:30:18.000: "name": "round_stats",
+08/31/2025 - 16:30:18.000: "round_number" : "3",
+08/31/2025 - 16:30:18.000: "fields" : "accountid,team,money,kills,deaths,assists,dmg,hsp,kdr,adr,mvp,ef,ud,3k,4k,5k,clutchk,firstk,pistolk,sniperk,blindk,bombk,firedmg,uniquek,dinks,chickenk"
+08/31/2025 - 16:30:18.000: "players" : {
+08/31/2025 - 16:30:18.000: "player_0" : "208135644,2,10250,19,23,9,2649,57.89,0.83,83,4,11,131,2,0,0,4,3,5,0,0,4,47,84,5,0"
+08/31/2025 - 16:30:18.000: }}JSON_END
+
+not a log line
+08/31/2025 - 16:30:19.000: Starting Freeze period
+`
+
+func TestScanner(t *testing.T) {
+	s := NewScanner(strings.NewReader(scannerLog))
+
+	var records []Record
+	for s.Scan() {
+		records = append(records, s.Record())
+	}
+
+	if err := s.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(records) != 4 {
+		t.Fatalf("Expected 4 records, got %d", len(records))
+	}
+
+	expected := []struct {
+		line int
+		typ  string
+		err  error
+	}{
+		{1, "WorldRoundEnd", nil},
+		{2, "JSONStatistics", nil},
+		{10, "", ErrorNoMatch},
+		{11, "FreezTimeStart", nil},
+	}
+
+	for i, e := range expected {
+		r := records[i]
+
+		if r.Line != e.line {
+			t.Errorf("record %d: expected line %d, got %d", i, e.line, r.Line)
+		}
+
+		if r.Err != e.err {
+			t.Errorf("record %d: expected error %v, got %v", i, e.err, r.Err)
+		}
+
+		if e.typ != "" && (r.Message == nil || r.Message.GetType() != e.typ) {
+			t.Errorf("record %d: expected %s, got %v", i, e.typ, r.Message)
+		}
+	}
+
+	stats := records[1].Message.(JSONStatistics)
+	if stats.RoundNumber != 3 || len(stats.Players) != 1 {
+		t.Errorf("JSON block not parsed completely: %+v", stats)
+	}
+
+	if n := strings.Count(records[1].Raw, "\n"); n != 6 {
+		t.Errorf("Expected raw JSON block of 7 lines, got %d", n+1)
+	}
+}
+
+func TestScanner_LongLinesAndCRLF(t *testing.T) {
+	text := strings.Repeat("a", 1<<20)
+	input := `08/31/2025 - 16:30:18.000: "Magixx<123><STEAM_1:0:123456><CT>" say "` + text + "\"\r\n" +
+		`08/31/2025 - 16:30:19.000: World triggered "Round_Start"` + "\r\n"
+
+	s := NewScanner(strings.NewReader(input))
+
+	if !s.Scan() {
+		t.Fatalf("Expected first record, got error %v", s.Err())
+	}
+
+	say, ok := s.Message().(PlayerSay)
+	if !ok || say.Text != text {
+		t.Fatalf("Expected PlayerSay with %d bytes of text, got %T", len(text), s.Message())
+	}
+
+	if !s.Scan() || s.Message().GetType() != "WorldRoundStart" || s.Record().Line != 2 {
+		t.Fatalf("Expected WorldRoundStart on line 2, got %+v", s.Record())
+	}
+
+	if s.Scan() {
+		t.Error("Expected end of input")
+	}
+}
+
+func TestScanner_IncompleteJSONBlock(t *testing.T) {
+	input := "08/31/2025 - 16:30:18.000: JSON_BEGIN{\n08/31/2025 - 16:30:18.000: \"name\": \"round_stats\","
+
+	s := NewScanner(strings.NewReader(input))
+
+	if !s.Scan() {
+		t.Fatal("Expected a record for the incomplete block")
+	}
+
+	if r := s.Record(); r.Err == nil || r.Line != 1 {
+		t.Errorf("Expected error on line 1, got %+v", r)
+	}
+
+	if s.Scan() {
+		t.Error("Expected end of input")
+	}
+}
+
+func TestScanner_ReadError(t *testing.T) {
+	readErr := errors.New("disk on fire")
+	r := io.MultiReader(
+		strings.NewReader("08/31/2025 - 16:30:19.000: World triggered \"Round_Start\"\n"),
+		iotest.ErrReader(readErr),
+	)
+
+	s := NewScanner(r)
+
+	if !s.Scan() {
+		t.Fatal("Expected first record")
+	}
+
+	if s.Scan() {
+		t.Error("Expected scanning to stop at read error")
+	}
+
+	if s.Err() != readErr {
+		t.Errorf("Expected read error, got %v", s.Err())
+	}
+}
+
+func TestParser_NewScanner(t *testing.T) {
+	p := NewParser(WithUnknown(UnknownSkip))
+	s := p.NewScanner(strings.NewReader("08/31/2025 - 16:30:19.000: something unknown\n08/31/2025 - 16:30:19.000: Match unpaused\n"))
+
+	if !s.Scan() || s.Message().GetType() != "MatchPause" {
+		t.Fatalf("Expected MatchPause from the parser's patterns, got %+v", s.Record())
+	}
+}
@@ -0,0 +1,262 @@
+package cs2log
+
+import "sort"
+
+// utilityWeapons are the weapons whose damage counts as utility damage
+var utilityWeapons = map[string]bool{
+	"hegrenade":  true,
+	"inferno":    true,
+	"molotov":    true,
+	"incgrenade": true,
+}
+
+// PlayerScore holds the statistics of a player derived from the events
+type PlayerScore struct {
+	Player       Player `json:"player"`
+	Kills        int    `json:"kills"`
+	Deaths       int    `json:"deaths"`
+	Assists      int    `json:"assists"`
+	FlashAssists int    `json:"flash_assists"`
+	Headshots    int    `json:"headshots"`
+	// Damage is the health damage dealt to enemies, without overkill
+	Damage int `json:"damage"`
+	// UtilityDamage is the part of Damage dealt with grenades and fire
+	UtilityDamage int `json:"utility_damage"`
+	// Rounds is the number of rounds that ended with the player on a team
+	Rounds int `json:"rounds"`
+}
+
+// HeadshotPct returns the percentage of kills that were headshots
+func (s PlayerScore) HeadshotPct() float64 {
+	if s.Kills == 0 {
+		return 0
+	}
+	return float64(s.Headshots) * 100 / float64(s.Kills)
+}
+
+// ADR returns the average damage per round
+func (s PlayerScore) ADR() float64 {
+	if s.Rounds == 0 {
+		return 0
+	}
+	return float64(s.Damage) / float64(s.Rounds)
+}
+
+// Scoreboard derives a scoreboard from kill, assist and damage events, so it
+// works with any log, not only the ones containing JSON statistics. Events
+// during the warmup are ignored and a restart of the game clears the board.
+// Players are keyed by an IdentityResolver, so every bot instance gets a
+// score of its own.
+type Scoreboard struct {
+	match  *Match
+	ids    *IdentityResolver
+	scores map[string]*PlayerScore
+	health healthTracker
+}
+
+// NewScoreboard returns an empty scoreboard for a match of the given format
+func NewScoreboard(opts ...MatchOption) *Scoreboard {
+	return &Scoreboard{
+		match:  NewMatch(opts...),
+		ids:    NewIdentityResolver(),
+		scores: make(map[string]*PlayerScore),
+		health: make(healthTracker),
+	}
+}
+
+// Update adds the next message to the scoreboard
+func (s *Scoreboard) Update(msg Message) {
+	wasLive := s.match.roundLive
+	s.match.Update(msg)
+	s.ids.Update(msg)
+
+	switch msg.(type) {
+	case WorldMatchStart, WorldRoundRestart:
+		s.scores = make(map[string]*PlayerScore)
+		s.health = make(healthTracker)
+		return
+	case FreezTimeStart, FreezePeriod:
+		s.health = make(healthTracker)
+		return
+	case TeamNotice, WorldRoundEnd:
+		if wasLive && !s.match.roundLive {
+			s.roundEnd()
+		}
+		return
+	}
+
+	if s.match.warmup {
+		return
+	}
+
+	switch msg := msg.(type) {
+	case PlayerKill:
+		s.score(msg.Victim).Deaths++
+		if msg.Attacker.Side != msg.Victim.Side {
+			a := s.score(msg.Attacker)
+			a.Kills++
+			if msg.Headshot {
+				a.Headshots++
+			}
+		}
+
+	case PlayerKilledBomb:
+		s.score(msg.Player).Deaths++
+
+	case PlayerKilledSuicide:
+		s.score(msg.Player).Deaths++
+
+	case PlayerKillAssist:
+		s.score(msg.Attacker).Assists++
+
+	case PlayerFlashAssist:
+		s.score(msg.Attacker).FlashAssists++
+
+	case PlayerAttack:
+		damage := s.health.damage(msg)
+		if msg.Attacker.Side != msg.Victim.Side {
+			a := s.score(msg.Attacker)
+			a.Damage += damage
+			if utilityWeapons[msg.Weapon] {
+				a.UtilityDamage += damage
+			}
+		}
+	}
+}
+
+// healthTracker keeps the health of the players during a round
+type healthTracker map[string]int
+
+// damage returns the health damage of an attack without overkill,
+// the log reports the full damage of the hit even if it was lethal
+func (h healthTracker) damage(msg PlayerAttack) int {
+	key := playerKey(msg.Victim)
+
+	before, ok := h[key]
+	if !ok {
+		before = 100
+	}
+	h[key] = msg.Health
+
+	if msg.Damage > before {
+		return before
+	}
+	return msg.Damage
+}
+
+// roundEnd counts the round for every player on a team
+func (s *Scoreboard) roundEnd() {
+	for _, p := range s.match.players {
+		if p.Side == "CT" || p.Side == "TERRORIST" {
+			s.score(p).Rounds++
+		}
+	}
+}
+
+// score returns the score of a player, creating it on first use
+func (s *Scoreboard) score(p Player) *PlayerScore {
+	key := s.ids.Key(p)
+
+	score, ok := s.scores[key]
+	if !ok {
+		score = &PlayerScore{}
+		s.scores[key] = score
+	}
+	score.Player = p
+
+	return score
+}
+
+// Scores returns the scores of all players keyed like Identity.Key, by
+// SteamID3 and bot instances by "BOT:<name>#<n>"
+func (s *Scoreboard) Scores() map[string]PlayerScore {
+	scores := make(map[string]PlayerScore, len(s.scores))
+	for key, score := range s.scores {
+		scores[key] = *score
+	}
+	return scores
+}
+
+// Ranking returns the scores sorted by kills, then deaths and name
+func (s *Scoreboard) Ranking() []PlayerScore {
+	ranking := make([]PlayerScore, 0, len(s.scores))
+	for _, score := range s.scores {
+		ranking = append(ranking, *score)
+	}
+
+	sort.Slice(ranking, func(i, j int) bool {
+		a, b := ranking[i], ranking[j]
+		if a.Kills != b.Kills {
+			return a.Kills > b.Kills
+		}
+		if a.Deaths != b.Deaths {
+			return a.Deaths < b.Deaths
+		}
+		return a.Player.Name < b.Player.Name
+	})
+
+	return ranking
+}
+
+// ScoreMismatch is a statistic the scoreboard and JSON statistics disagree on
+type ScoreMismatch struct {
+	Player Player `json:"player"`
+	// Field is the name of the statistic, e.g. "kills"
+	Field string `json:"field"`
+	// Derived is the value of the scoreboard
+	Derived int `json:"derived"`
+	// Reported is the value of the JSON statistics
+	Reported int `json:"reported"`
+}
+
+// sortMismatches sorts mismatches by player name and field
+func sortMismatches(mismatches []ScoreMismatch) {
+	sort.Slice(mismatches, func(i, j int) bool {
+		if mismatches[i].Player.Name != mismatches[j].Player.Name {
+			return mismatches[i].Player.Name < mismatches[j].Player.Name
+		}
+		return mismatches[i].Field < mismatches[j].Field
+	})
+}
+
+// Compare cross-checks the scoreboard against the JSON statistics logged at
+// the end of the same round and returns the statistics that differ. Players
+// are matched by account ID, bots and players missing on either side are
+// skipped.
+func (s *Scoreboard) Compare(stats JSONStatistics) []ScoreMismatch {
+	byAccount := make(map[int]*PlayerScore)
+	for _, score := range s.scores {
+		if id, ok := accountID(score.Player.SteamID); ok {
+			byAccount[id] = score
+		}
+	}
+
+	var mismatches []ScoreMismatch
+	for _, ps := range stats.Players {
+		score, ok := byAccount[ps.AccountID]
+		if !ok || ps.AccountID == 0 {
+			continue
+		}
+
+		fields := []struct {
+			name              string
+			derived, reported int
+		}{
+			{"kills", score.Kills, ps.Kills},
+			{"deaths", score.Deaths, ps.Deaths},
+			{"assists", score.Assists, ps.Assists},
+			{"damage", score.Damage, ps.Damage},
+			{"utility_damage", score.UtilityDamage, ps.UtilityDamage},
+		}
+
+		for _, f := range fields {
+			if f.derived != f.reported {
+				mismatches = append(mismatches, ScoreMismatch{score.Player, f.name, f.derived, f.reported})
+			}
+		}
+	}
+
+	sortMismatches(mismatches)
+
+	return mismatches
+}
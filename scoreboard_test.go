@@ -0,0 +1,181 @@
+package cs2log
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const (
+	magixx = `"Magixx<2><[U:1:111]><CT>"`
+	jame   = `"Jame<3><[U:1:333]><CT>"`
+	zont1x = `"Zont1x<4><[U:1:222]><TERRORIST>"`
+	kyle   = `"Kyle<5><BOT><TERRORIST>"`
+)
+
+func attack(attacker, victim, weapon string, damage, health int) string {
+	return fmt.Sprintf(`%s [0 0 0] attacked %s [0 0 0] with "%s" (damage "%d") (damage_armor "0") (health "%d") (armor "0") (hitgroup "chest")`,
+		attacker, victim, weapon, damage, health)
+}
+
+func kill(attacker, victim, weapon string, headshot bool) string {
+	l := fmt.Sprintf(`%s [0 0 0] killed %s [0 0 0] with "%s"`, attacker, victim, weapon)
+	if headshot {
+		l += " (headshot)"
+	}
+	return l
+}
+
+// scoreboardLog is a round on de_nuke, followed by its JSON statistics
+var scoreboardLog = strings.Join([]string{
+	`World triggered "Match_Start" on "de_nuke"`,
+	`Starting Freeze period`,
+	`World triggered "Round_Start"`,
+	attack(magixx, zont1x, "m4a1", 30, 70),
+	attack(magixx, zont1x, "hegrenade", 20, 50),
+	attack(jame, magixx, "m4a1", 10, 90),
+	attack(jame, zont1x, "awp", 448, 0),
+	kill(jame, zont1x, "awp", true),
+	magixx + ` assisted killing ` + zont1x,
+	magixx + ` flash-assisted killing ` + zont1x,
+	attack(kyle, magixx, "ak47", 150, 0),
+	kill(kyle, magixx, "ak47", false),
+	`Team "CT" triggered "SFUI_Notice_Target_Saved" (CT "1") (T "0")`,
+	`World triggered "Round_End"`,
+	`JSON_BEGIN{`,
+	`"name": "round_stats",`,
+	`"round_number" : "1",`,
+	`"fields" : "accountid,team,money,kills,deaths,assists,dmg,hsp,kdr,adr,mvp,ef,ud,3k,4k,5k,clutchk,firstk,pistolk,sniperk,blindk,bombk,firedmg,uniquek,dinks,chickenk"`,
+	`"players" : {`,
+	`"player_0" : "111,3,800,0,1,1,50,0,0,50,0,0,20,0,0,0,0,0,0,0,0,0,0,0,0,0"`,
+	`"player_1" : "333,3,800,1,0,0,60,100,1,60,1,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0"`,
+	`}}JSON_END`,
+}, "\n")
+
+func TestScoreboard(t *testing.T) {
+	s := NewScoreboard()
+
+	var stats JSONStatistics
+	for _, msg := range parseLog(t, scoreboardLog) {
+		s.Update(msg)
+		if js, ok := msg.(JSONStatistics); ok {
+			stats = js
+		}
+	}
+
+	scores := s.Scores()
+
+	expected := map[string]PlayerScore{
+		"[U:1:111]": {Deaths: 1, Assists: 1, FlashAssists: 1, Damage: 50, UtilityDamage: 20, Rounds: 1},
+		"[U:1:333]": {Kills: 1, Headshots: 1, Damage: 50, Rounds: 1},
+		"[U:1:222]": {Deaths: 1, Rounds: 1},
+		"BOT:Kyle#1":  {Kills: 1, Damage: 90, Rounds: 1},
+	}
+
+	if len(scores) != len(expected) {
+		t.Fatalf("Expected %d players, got %d", len(expected), len(scores))
+	}
+
+	for key, e := range expected {
+		got := scores[key]
+		e.Player = got.Player
+		if got != e {
+			t.Errorf("%s: expected %+v, got %+v", key, e, got)
+		}
+	}
+
+	if hs := scores["[U:1:333]"].HeadshotPct(); hs != 100 {
+		t.Errorf("Expected 100%% headshots, got %v", hs)
+	}
+
+	if adr := scores["BOT:Kyle#1"].ADR(); adr != 90 {
+		t.Errorf("Expected ADR 90, got %v", adr)
+	}
+
+	if top := s.Ranking()[0].Player.Name; top != "Jame" {
+		t.Errorf("Expected Jame on top, got %s", top)
+	}
+
+	mismatches := s.Compare(stats)
+	if len(mismatches) != 1 {
+		t.Fatalf("Expected 1 mismatch, got %+v", mismatches)
+	}
+
+	if m := mismatches[0]; m.Player.Name != "Jame" || m.Field != "damage" || m.Derived != 50 || m.Reported != 60 {
+		t.Errorf("Unexpected mismatch %+v", m)
+	}
+}
+
+func TestScoreboard_WarmupAndRestart(t *testing.T) {
+	s := NewScoreboard()
+
+	for _, msg := range parseLog(t, strings.Join([]string{
+		`World triggered "Warmup_Start"`,
+		kill(kyle, magixx, "ak47", false),
+		`World triggered "Match_Start" on "de_nuke"`,
+		kill(kyle, magixx, "ak47", false),
+		`World triggered "Restart_Round_(1_second)"`,
+		`Starting Freeze period`,
+		kill(magixx, kyle, "m4a1", false),
+	}, "\n")) {
+		s.Update(msg)
+	}
+
+	scores := s.Scores()
+	if len(scores) != 2 || scores["[U:1:111]"].Kills != 1 || scores["BOT:Kyle#1"].Kills != 0 {
+		t.Errorf("Expected only the kill after the restart, got %+v", scores)
+	}
+}
+
+func TestScoreboard_Identities(t *testing.T) {
+	s := NewScoreboard()
+
+	kyle2 := `"Kyle<7><BOT><TERRORIST>"`
+	magixxSteam2 := `"Magixx<2><STEAM_1:1:55><CT>"`
+
+	for _, msg := range parseLog(t, strings.Join([]string{
+		`World triggered "Match_Start" on "de_nuke"`,
+		kill(kyle, jame, "ak47", false),
+		`"Kyle<5><BOT><TERRORIST>" disconnected (reason "Kicked by Console")`,
+		`"Kyle<7><BOT><>" connected, address ""`,
+		kill(kyle2, magixx, "ak47", true),
+		kill(magixxSteam2, kyle2, "m4a1", false),
+	}, "\n")) {
+		s.Update(msg)
+	}
+
+	scores := s.Scores()
+	if len(scores) != 4 {
+		t.Fatalf("Expected 4 players, got %+v", scores)
+	}
+
+	// both bots named Kyle are scored apart
+	if k := scores["BOT:Kyle#1"]; k.Kills != 1 || k.Deaths != 0 {
+		t.Errorf("Expected the first Kyle to have 1 kill, got %+v", k)
+	}
+	if k := scores["BOT:Kyle#2"]; k.Kills != 1 || k.Headshots != 1 || k.Deaths != 1 {
+		t.Errorf("Expected the second Kyle to have 1 kill and 1 death, got %+v", k)
+	}
+
+	// both formats of the SteamID of Magixx are the same player
+	if m := scores["[U:1:111]"]; m.Kills != 1 || m.Deaths != 1 {
+		t.Errorf("Expected Magixx to have 1 kill and 1 death, got %+v", m)
+	}
+}
+
+func TestAccountID(t *testing.T) {
+	tests := map[string]int{
+		"[U:1:109933575]":    109933575,
+		"STEAM_1:1:54966787": 109933575,
+	}
+
+	for steamID, expected := range tests {
+		if id, ok := accountID(steamID); !ok || id != expected {
+			t.Errorf("accountID(%s) = %d, %v, expected %d", steamID, id, ok, expected)
+		}
+	}
+
+	if _, ok := accountID("BOT"); ok {
+		t.Error("Expected no account ID for bots")
+	}
+}
@@ -35,69 +35,38 @@ func (ps *ParserState) Reset() {
 // ParseLines takes multiple log lines and returns all parsed events
 // This handles both single-line events and multi-line JSON blocks correctly
 func ParseLines(lines []string) ([]Message, []error) {
-	state := NewParserState()
-	var messages []Message
-	var errors []error
-	
-	for _, line := range lines {
-		msg, err := ParseStateful(line, state)
-		
-		if err != nil {
-			errors = append(errors, err)
-			continue
-		}
-		
-		if msg != nil {
-			messages = append(messages, msg)
-		}
-	}
-	
-	// Check if there's an incomplete JSON block at the end
-	if state.InJSONBlock {
-		errors = append(errors, fmt.Errorf("incomplete JSON block at end of input"))
-	}
-	
-	return messages, errors
+	return parseLines(lines, ParseStateful)
 }
 
 // ParseLinesOrdered takes multiple log lines and uses ParseOrdered for single-line events
 // This provides enhanced parsing with correct pattern priority
 func ParseLinesOrdered(lines []string) ([]Message, []error) {
-	state := NewParserState()
-	var messages []Message
-	var errors []error
-	
-	for _, line := range lines {
-		msg, err := parseStatefulWithParser(line, state, ParseOrdered)
-		
-		if err != nil {
-			errors = append(errors, err)
-			continue
-		}
-		
-		if msg != nil {
-			messages = append(messages, msg)
-		}
-	}
-	
-	// Check if there's an incomplete JSON block at the end
-	if state.InJSONBlock {
-		errors = append(errors, fmt.Errorf("incomplete JSON block at end of input"))
-	}
-	
-	return messages, errors
+	return parseLines(lines, func(line string, state *ParserState) (Message, error) {
+		return parseStatefulWithParser(line, state, ParseOrdered)
+	})
 }
 
 // ParseLinesEnhanced takes multiple log lines and uses ParseEnhanced for single-line events
 // This provides access to all custom event types
 func ParseLinesEnhanced(lines []string) ([]Message, []error) {
+	return parseLines(lines, func(line string, state *ParserState) (Message, error) {
+		return parseStatefulWithParser(line, state, ParseEnhanced)
+	})
+}
+
+// parseLines runs a stateful parse function over all lines
+func parseLines(lines []string, parse func(string, *ParserState) (Message, error)) ([]Message, []error) {
 	state := NewParserState()
 	var messages []Message
 	var errors []error
 	
 	for _, line := range lines {
-		msg, err := parseStatefulWithParser(line, state, ParseEnhanced)
+		msg, err := parse(line, state)
 		
+		if err == ErrorSkipped {
+			continue
+		}
+
 		if err != nil {
 			errors = append(errors, err)
 			continue
@@ -125,9 +94,15 @@ func ParseStateful(line string, state *ParserState) (Message, error) {
 
 // parseStatefulWithParser is the internal implementation that accepts a parser function
 func parseStatefulWithParser(line string, state *ParserState, parser func(string) (Message, error)) (Message, error) {
+	return defaultParser.parseStateful(line, state, parser)
+}
+
+// parseStateful buffers JSON blocks using the parser's line format, time zone
+// and strictness and hands all other lines to the parser function
+func (p *Parser) parseStateful(line string, state *ParserState, parser func(string) (Message, error)) (Message, error) {
 	// First extract timestamp and content
-	result := LogLinePattern.FindStringSubmatch(line)
-	if result == nil {
+	timestamp, content, ok := p.split(line)
+	if !ok {
 		// If we're in a JSON block and get an invalid line, treat it as an error
 		if state.InJSONBlock {
 			state.Reset()
@@ -136,13 +111,10 @@ func parseStatefulWithParser(line string, state *ParserState, parser func(string
 		return nil, ErrorNoMatch
 	}
 
-	timestamp := result[1]
-	content := result[2]
-
 	// Check if this is the start of a JSON block
 	if strings.HasPrefix(content, "JSON_BEGIN{") {
 		// Parse the timestamp
-		ti, err := time.Parse("01/02/2006 - 15:04:05.000", timestamp)
+		ti, err := p.parseTime(timestamp)
 		if err != nil {
 			return nil, err
 		}
@@ -168,8 +140,11 @@ func parseStatefulWithParser(line string, state *ParserState, parser func(string
 		// Check if this is the end of the JSON block
 		if strings.HasSuffix(content, "}}JSON_END") {
 			// Parse the complete JSON block
-			msg := parseJSONBlock(state.JSONStartTime, state.JSONBuffer)
+			msg, err := decodeJSONBlock(state.JSONStartTime, state.JSONBuffer)
 			state.Reset()
+			if err != nil && p.strict {
+				return nil, fmt.Errorf("invalid JSON block: %w", err)
+			}
 			return msg, nil
 		}
 
@@ -182,6 +157,13 @@ func parseStatefulWithParser(line string, state *ParserState, parser func(string
 
 // parseJSONBlock parses a complete JSON statistics block
 func parseJSONBlock(timestamp time.Time, lines []string) Message {
+	stats, _ := decodeJSONBlock(timestamp, lines)
+	return stats
+}
+
+// decodeJSONBlock parses a complete JSON statistics block, if the block is
+// not valid JSON the statistics only hold RawJSON and the error is returned
+func decodeJSONBlock(timestamp time.Time, lines []string) (JSONStatistics, error) {
 	// Join all lines to form the JSON content
 	var jsonLines []string
 	
@@ -290,7 +272,7 @@ func parseJSONBlock(timestamp time.Time, lines []string) Message {
 		}
 	}
 
-	return stats
+	return stats, err
 }
 
 // parsePlayerStatistics parses player statistics from comma-separated values
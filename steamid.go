@@ -0,0 +1,242 @@
+package cs2log
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrorInvalidSteamID error when a SteamID is empty or corrupted
+	ErrorInvalidSteamID = errors.New("invalid steam id")
+)
+
+// SteamIDType tells Steam accounts from the placeholders the log uses for
+// players without one
+type SteamIDType int
+
+// Types of SteamIDs
+const (
+	// SteamIDInvalid is an empty or corrupted SteamID
+	SteamIDInvalid SteamIDType = iota
+	// SteamIDAccount is the SteamID of an individual Steam account
+	SteamIDAccount
+	// SteamIDBot is the "BOT" every bot shares
+	SteamIDBot
+	// SteamIDGOTV is the SteamID of the GOTV relay
+	SteamIDGOTV
+	// SteamIDConsole is the "Console" of messages by the server
+	SteamIDConsole
+)
+
+func (t SteamIDType) String() string {
+	switch t {
+	case SteamIDAccount:
+		return "account"
+	case SteamIDBot:
+		return "bot"
+	case SteamIDGOTV:
+		return "gotv"
+	case SteamIDConsole:
+		return "console"
+	}
+	return "invalid"
+}
+
+// steamID64Individual is the SteamID64 of account 0 in the public universe,
+// the type and instance bits of individual accounts are set
+const steamID64Individual = 1<<52 | 1<<32
+
+// SteamID is a parsed SteamID. Only accounts have a universe and an account
+// ID, the account ID is the number JSON statistics identify players by.
+type SteamID struct {
+	Type SteamIDType `json:"type"`
+	// Universe is the Steam universe, 1 for the public one
+	Universe int    `json:"universe,omitempty"`
+	Account  uint32 `json:"account,omitempty"`
+}
+
+// NewSteamID returns the SteamID of the account with the given ID in the
+// public universe
+func NewSteamID(account uint32) SteamID {
+	return SteamID{Type: SteamIDAccount, Universe: 1, Account: account}
+}
+
+// ParseSteamID parses a SteamID as logged, "[U:1:29384012]", "STEAM_1:0:123",
+// a SteamID64 like "76561198000000000", "BOT", "GOTV" or "Console"
+func ParseSteamID(s string) (SteamID, error) {
+	s = strings.TrimSpace(s)
+
+	switch s {
+	case botSteamID:
+		return SteamID{Type: SteamIDBot}, nil
+	case "GOTV":
+		return SteamID{Type: SteamIDGOTV}, nil
+	case "Console":
+		return SteamID{Type: SteamIDConsole}, nil
+	}
+
+	var id SteamID
+	var ok bool
+	switch {
+	case strings.HasPrefix(s, "[U:") && strings.HasSuffix(s, "]"):
+		id, ok = parseSteamID3(s[len("[U:") : len(s)-1])
+	case strings.HasPrefix(s, "STEAM_"):
+		id, ok = parseSteamID2(s[len("STEAM_"):])
+	default:
+		id, ok = parseSteamID64(s)
+	}
+
+	if !ok || !id.Valid() {
+		return SteamID{}, fmt.Errorf("%w: %q", ErrorInvalidSteamID, s)
+	}
+
+	return id, nil
+}
+
+// parseSteamID3 parses "universe:account"
+func parseSteamID3(s string) (SteamID, bool) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return SteamID{}, false
+	}
+
+	universe, errU := strconv.Atoi(parts[0])
+	account, errA := strconv.ParseUint(parts[1], 10, 32)
+	if errU != nil || errA != nil {
+		return SteamID{}, false
+	}
+
+	return SteamID{Type: SteamIDAccount, Universe: universe, Account: uint32(account)}, true
+}
+
+// parseSteamID2 parses "X:Y:Z", universe 0 is the public universe of old
+// games that did not know universes yet
+func parseSteamID2(s string) (SteamID, bool) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return SteamID{}, false
+	}
+
+	universe, errX := strconv.Atoi(parts[0])
+	y, errY := strconv.ParseUint(parts[1], 10, 32)
+	z, errZ := strconv.ParseUint(parts[2], 10, 31)
+	if errX != nil || errY != nil || errZ != nil || y > 1 {
+		return SteamID{}, false
+	}
+
+	if universe == 0 {
+		universe = 1
+	}
+
+	return SteamID{Type: SteamIDAccount, Universe: universe, Account: uint32(z*2 + y)}, true
+}
+
+// parseSteamID64 parses the decimal SteamID64 of an individual account
+func parseSteamID64(s string) (SteamID, bool) {
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || n>>52&0xf != 1 {
+		return SteamID{}, false
+	}
+
+	return SteamID{Type: SteamIDAccount, Universe: int(n >> 56), Account: uint32(n)}, true
+}
+
+// Valid reports whether the SteamID is a Steam account, bots, GOTV and
+// the console are valid as well
+func (id SteamID) Valid() bool {
+	switch id.Type {
+	case SteamIDAccount:
+		return id.Universe >= 1 && id.Universe <= 4 && id.Account != 0
+	case SteamIDBot, SteamIDGOTV, SteamIDConsole:
+		return true
+	}
+	return false
+}
+
+// IsAccount reports whether the SteamID belongs to a Steam account
+func (id SteamID) IsAccount() bool {
+	return id.Type == SteamIDAccount && id.Valid()
+}
+
+// IsBot reports whether the SteamID is the one of bots
+func (id SteamID) IsBot() bool {
+	return id.Type == SteamIDBot
+}
+
+// IsGOTV reports whether the SteamID is the one of the GOTV relay
+func (id SteamID) IsGOTV() bool {
+	return id.Type == SteamIDGOTV
+}
+
+// IsConsole reports whether the SteamID is the one of the server console
+func (id SteamID) IsConsole() bool {
+	return id.Type == SteamIDConsole
+}
+
+// AccountID returns the account ID to join with PlayerStatistics.AccountID,
+// 0 if the SteamID is not an account
+func (id SteamID) AccountID() int {
+	if !id.IsAccount() {
+		return 0
+	}
+	return int(id.Account)
+}
+
+// SteamID64 returns the 64 bit SteamID the Steam Web API uses, 0 if the
+// SteamID is not an account
+func (id SteamID) SteamID64() uint64 {
+	if !id.IsAccount() {
+		return 0
+	}
+	return uint64(id.Universe)<<56 | steamID64Individual | uint64(id.Account)
+}
+
+// SteamID2 returns the SteamID in the "STEAM_1:y:z" format, empty if the
+// SteamID is not an account
+func (id SteamID) SteamID2() string {
+	if !id.IsAccount() {
+		return ""
+	}
+	return fmt.Sprintf("STEAM_%d:%d:%d", id.Universe, id.Account&1, id.Account>>1)
+}
+
+// SteamID3 returns the SteamID in the "[U:1:n]" format the log uses, empty
+// if the SteamID is not an account
+func (id SteamID) SteamID3() string {
+	if !id.IsAccount() {
+		return ""
+	}
+	return fmt.Sprintf("[U:%d:%d]", id.Universe, id.Account)
+}
+
+// String returns the SteamID as logged, the SteamID3 for accounts and
+// "BOT", "GOTV" or "Console" for the others
+func (id SteamID) String() string {
+	switch id.Type {
+	case SteamIDBot:
+		return botSteamID
+	case SteamIDGOTV:
+		return "GOTV"
+	case SteamIDConsole:
+		return "Console"
+	}
+	return id.SteamID3()
+}
+
+// Steam returns the parsed SteamID of the player, of type SteamIDInvalid if
+// the SteamID is empty or corrupted. The GOTV relay is logged as a bot named
+// "GOTV" or "SourceTV".
+func (p Player) Steam() SteamID {
+	id, err := ParseSteamID(p.SteamID)
+	if err != nil {
+		return SteamID{}
+	}
+
+	if id.IsBot() && (p.Name == "GOTV" || p.Name == "SourceTV") {
+		return SteamID{Type: SteamIDGOTV}
+	}
+
+	return id
+}
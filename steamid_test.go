@@ -0,0 +1,108 @@
+package cs2log
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSteamID(t *testing.T) {
+	formats := []string{
+		"[U:1:109933575]",
+		"STEAM_1:1:54966787",
+		"STEAM_0:1:54966787",
+		"76561198070199303",
+	}
+
+	for _, s := range formats {
+		id, err := ParseSteamID(s)
+		if err != nil {
+			t.Fatalf("ParseSteamID(%s) failed: %v", s, err)
+		}
+
+		if !id.IsAccount() || id.AccountID() != 109933575 {
+			t.Errorf("%s: unexpected account %+v", s, id)
+		}
+		if id.SteamID64() != 76561198070199303 {
+			t.Errorf("%s: unexpected SteamID64 %d", s, id.SteamID64())
+		}
+		if id.SteamID2() != "STEAM_1:1:54966787" {
+			t.Errorf("%s: unexpected SteamID2 %s", s, id.SteamID2())
+		}
+		if id.SteamID3() != "[U:1:109933575]" || id.String() != id.SteamID3() {
+			t.Errorf("%s: unexpected SteamID3 %s", s, id.SteamID3())
+		}
+	}
+
+	if id, _ := ParseSteamID("[U:1:29384012]"); id != NewSteamID(29384012) {
+		t.Errorf("Expected NewSteamID to match the parsed SteamID, got %+v", id)
+	}
+	if s := NewSteamID(29384012).SteamID2(); s != "STEAM_1:0:14692006" {
+		t.Errorf("Expected STEAM_1:0:14692006, got %s", s)
+	}
+}
+
+func TestParseSteamID_Special(t *testing.T) {
+	tests := map[string]SteamIDType{
+		"BOT":     SteamIDBot,
+		"GOTV":    SteamIDGOTV,
+		"Console": SteamIDConsole,
+	}
+
+	for s, expected := range tests {
+		id, err := ParseSteamID(s)
+		if err != nil {
+			t.Fatalf("ParseSteamID(%s) failed: %v", s, err)
+		}
+
+		if id.Type != expected || !id.Valid() || id.IsAccount() {
+			t.Errorf("%s: unexpected SteamID %+v", s, id)
+		}
+		if id.AccountID() != 0 || id.SteamID64() != 0 || id.SteamID3() != "" {
+			t.Errorf("%s: expected no account, got %+v", s, id)
+		}
+		if id.String() != s {
+			t.Errorf("%s: unexpected string %s", s, id)
+		}
+	}
+}
+
+func TestParseSteamID_Invalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"[U:1:",
+		"[U:1:abc]",
+		"[U:1:0]",
+		"[U:9:123]",
+		"[U:1:123:1]",
+		"STEAM_ID_PENDING",
+		"STEAM_1:2:123",
+		"STEAM_1:0",
+		"12345",
+		"bot",
+	}
+
+	for _, s := range invalid {
+		id, err := ParseSteamID(s)
+		if !errors.Is(err, ErrorInvalidSteamID) {
+			t.Errorf("ParseSteamID(%q) = %v, expected ErrorInvalidSteamID", s, err)
+		}
+		if id.Type != SteamIDInvalid || id.Valid() {
+			t.Errorf("%q: expected an invalid SteamID, got %+v", s, id)
+		}
+	}
+}
+
+func TestPlayerSteam(t *testing.T) {
+	if id := NewPlayer("Magixx", "2", "[U:1:111]", "CT").Steam(); id.AccountID() != 111 {
+		t.Errorf("Expected account 111, got %+v", id)
+	}
+	if id := NewPlayer("Kyle", "5", "BOT", "TERRORIST").Steam(); !id.IsBot() {
+		t.Errorf("Expected a bot, got %+v", id)
+	}
+	if id := NewPlayer("GOTV", "1", "BOT", "").Steam(); !id.IsGOTV() {
+		t.Errorf("Expected GOTV, got %+v", id)
+	}
+	if id := NewPlayer("Player-Name", "12", "[U:1:29384", "CT").Steam(); id.Valid() {
+		t.Errorf("Expected a corrupted SteamID to be invalid, got %+v", id)
+	}
+}
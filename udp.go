@@ -0,0 +1,136 @@
+package cs2log
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"net"
+	"strings"
+)
+
+var (
+	// ErrorInvalidPacket error when a packet is not a log packet
+	ErrorInvalidPacket = errors.New("invalid log packet")
+	// ErrorInvalidSecret error when the secret of a log packet doesn't match
+	ErrorInvalidSecret = errors.New("invalid log secret")
+)
+
+const (
+	// udpHeader starts every out-of-band packet of the game server
+	udpHeader = "\xff\xff\xff\xff"
+	// udpPlain marks a log packet without secret
+	udpPlain = 'R'
+	// udpSecret marks a log packet followed by sv_logsecret
+	udpSecret = 'S'
+)
+
+// DecodeLogPacket returns the log lines of a packet sent by logaddress_add.
+// Packets are "\xff\xff\xff\xffR" followed by the log line or, when the server
+// has sv_logsecret set, "\xff\xff\xff\xffS" followed by the secret and the line.
+// The secret must be followed by the "L " of the line, so a secret is never
+// accepted as the start of a longer one. With an empty secret only packets
+// without secret are accepted.
+func DecodeLogPacket(packet []byte, secret string) ([]string, error) {
+	if len(packet) < len(udpHeader)+1 || string(packet[:len(udpHeader)]) != udpHeader {
+		return nil, ErrorInvalidPacket
+	}
+
+	payload := packet[len(udpHeader)+1:]
+
+	switch packet[len(udpHeader)] {
+	case udpPlain:
+		if secret != "" {
+			return nil, ErrorInvalidSecret
+		}
+	case udpSecret:
+		if secret == "" || !validSecret(payload, secret) {
+			return nil, ErrorInvalidSecret
+		}
+		payload = payload[len(secret):]
+	default:
+		return nil, ErrorInvalidPacket
+	}
+
+	text := strings.TrimRight(string(payload), "\x00\r\n")
+	if text == "" {
+		return nil, nil
+	}
+
+	lines := strings.Split(text, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSuffix(lines[i], "\r")
+	}
+
+	return lines, nil
+}
+
+// validSecret reports whether the payload starts with exactly the secret,
+// followed by the "L " of the log line, compared in constant time
+func validSecret(payload []byte, secret string) bool {
+	if len(payload) < len(secret) || !bytes.HasPrefix(payload[len(secret):], []byte("L ")) {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(payload[:len(secret)], []byte(secret)) == 1
+}
+
+// UDPReceiver receives logs the game servers send with logaddress_add.
+// Every server, identified by its address, gets its own ParserState, so
+// JSON statistics blocks of servers logging at the same time never mix.
+type UDPReceiver struct {
+	// Secret is the sv_logsecret of the servers, empty if they don't use one
+	Secret string
+	// Parser parses the lines, nil uses ParseStateful
+	Parser *Parser
+	// Handler receives every record, tagged with the address of the server.
+	// Invalid packets and parse errors are passed as records with Err set.
+	Handler func(Record)
+	// MaxServers limits the number of servers whose parse state is kept, the
+	// state of the server heard from least recently is dropped first.
+	// Zero uses DefaultMaxServers.
+	MaxServers int
+
+	servers serverStates
+}
+
+// Serve reads packets from conn until it is closed and passes the parsed
+// records to the Handler. It returns nil when conn was closed and the
+// read error otherwise.
+func (u *UDPReceiver) Serve(conn net.PacketConn) error {
+	buf := make([]byte, 64*1024)
+
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		u.HandlePacket(addr.String(), buf[:n])
+	}
+}
+
+// HandlePacket parses a single packet received from server, it can be used
+// to feed packets read by other means than Serve
+func (u *UDPReceiver) HandlePacket(server string, packet []byte) {
+	lines, err := DecodeLogPacket(packet, u.Secret)
+	if err != nil {
+		u.handle(Record{Server: server, Raw: string(packet), Err: err})
+		return
+	}
+
+	u.servers.get(server, u.Parser, u.MaxServers).feed(server, lines, u.handle)
+}
+
+// Forget drops the parse state of a server, e.g. after it shut down
+func (u *UDPReceiver) Forget(server string) {
+	u.servers.forget(server)
+}
+
+func (u *UDPReceiver) handle(rec Record) {
+	if u.Handler != nil {
+		u.Handler(rec)
+	}
+}
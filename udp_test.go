@@ -0,0 +1,168 @@
+package cs2log
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func packet(secret string, line string) []byte {
+	if secret == "" {
+		return []byte("\xff\xff\xff\xffR" + line + "\n\x00")
+	}
+	return []byte("\xff\xff\xff\xffS" + secret + line + "\n\x00")
+}
+
+func TestDecodeLogPacket(t *testing.T) {
+	line := `L 08/31/2025 - 16:30:19.000: World triggered "Round_Start"`
+
+	tests := []struct {
+		name   string
+		packet []byte
+		secret string
+		lines  []string
+		err    error
+	}{
+		{"plain", packet("", line), "", []string{line}, nil},
+		{"secret", packet("hunter2", line), "hunter2", []string{line}, nil},
+		{"wrong secret", packet("hunter3", line), "hunter2", nil, ErrorInvalidSecret},
+		{"missing secret", packet("", line), "hunter2", nil, ErrorInvalidSecret},
+		{"longer secret", packet("hunter2", line), "hunter", nil, ErrorInvalidSecret},
+		{"shorter secret", packet("hunter", line), "hunter2", nil, ErrorInvalidSecret},
+		{"unexpected secret", packet("hunter2", line), "", nil, ErrorInvalidSecret},
+		{"no header", []byte(line), "", nil, ErrorInvalidPacket},
+		{"unknown type", []byte("\xff\xff\xff\xffX" + line), "", nil, ErrorInvalidPacket},
+		{"multiple lines", []byte("\xff\xff\xff\xffRa\r\nb\n\x00"), "", []string{"a", "b"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := DecodeLogPacket(tt.packet, tt.secret)
+
+			if err != tt.err {
+				t.Errorf("Expected error %v, got %v", tt.err, err)
+			}
+
+			if !reflect.DeepEqual(tt.lines, lines) {
+				t.Errorf("Expected %q, got %q", tt.lines, lines)
+			}
+		})
+	}
+}
+
+func TestUDPReceiver_InterleavedServers(t *testing.T) {
+	var records []Record
+	u := &UDPReceiver{
+		Secret:  "s3cret",
+		Handler: func(r Record) { records = append(records, r) },
+	}
+
+	blockA := []string{
+		`L 08/31/2025 - 16:30:18.000: JSON_BEGIN{`,
+		`L 08/31/2025 - 16:30:18.000: "name": "round_stats",`,
+		`L 08/31/2025 - 16:30:18.000: "round_number" : "1",`,
+		`L 08/31/2025 - 16:30:18.000: "players" : {`,
+		`L 08/31/2025 - 16:30:18.000: }}JSON_END`,
+	}
+	blockB := []string{
+		`L 08/31/2025 - 16:30:18.500: JSON_BEGIN{`,
+		`L 08/31/2025 - 16:30:18.500: "name": "round_stats",`,
+		`L 08/31/2025 - 16:30:18.500: "round_number" : "7",`,
+		`L 08/31/2025 - 16:30:18.500: "players" : {`,
+		`L 08/31/2025 - 16:30:18.500: }}JSON_END`,
+	}
+
+	for i := range blockA {
+		u.HandlePacket("10.0.0.1:27015", packet("s3cret", blockA[i]))
+		u.HandlePacket("10.0.0.2:27015", packet("s3cret", blockB[i]))
+	}
+	u.HandlePacket("10.0.0.3:27015", packet("wrong", blockA[0]))
+
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d: %+v", len(records), records)
+	}
+
+	rounds := map[string]int{}
+	for _, r := range records[:2] {
+		stats, ok := r.Message.(JSONStatistics)
+		if !ok || r.Err != nil {
+			t.Fatalf("Expected JSONStatistics, got %+v", r)
+		}
+		rounds[r.Server] = stats.RoundNumber
+	}
+
+	if rounds["10.0.0.1:27015"] != 1 || rounds["10.0.0.2:27015"] != 7 {
+		t.Errorf("JSON blocks of servers mixed up: %v", rounds)
+	}
+
+	if records[2].Err != ErrorInvalidSecret || records[2].Server != "10.0.0.3:27015" {
+		t.Errorf("Expected invalid secret from third server, got %+v", records[2])
+	}
+}
+
+func TestUDPReceiver_MaxServers(t *testing.T) {
+	var records []Record
+	u := &UDPReceiver{
+		MaxServers: 2,
+		Handler:    func(r Record) { records = append(records, r) },
+	}
+
+	u.HandlePacket("10.0.0.1:27015", packet("", `L 08/31/2025 - 16:30:18.000: JSON_BEGIN{`))
+	u.HandlePacket("10.0.0.2:27015", packet("", `L 08/31/2025 - 16:30:18.000: JSON_BEGIN{`))
+	u.HandlePacket("10.0.0.1:27015", packet("", `L 08/31/2025 - 16:30:18.000: "name": "round_stats",`))
+
+	// the second server was heard from least recently
+	u.HandlePacket("10.0.0.3:27015", packet("", `L 08/31/2025 - 16:30:19.000: World triggered "Round_Start"`))
+
+	if len(u.servers.servers) != 2 || u.servers.servers["10.0.0.2:27015"] != nil {
+		t.Fatalf("Expected the state of 10.0.0.2:27015 to be dropped, got %v", u.servers.servers)
+	}
+
+	u.HandlePacket("10.0.0.1:27015", packet("", `L 08/31/2025 - 16:30:18.000: "round_number" : "3",`))
+	u.HandlePacket("10.0.0.1:27015", packet("", `L 08/31/2025 - 16:30:18.000: "players" : {`))
+	u.HandlePacket("10.0.0.1:27015", packet("", `L 08/31/2025 - 16:30:18.000: }}JSON_END`))
+
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d: %+v", len(records), records)
+	}
+
+	if stats, ok := records[1].Message.(JSONStatistics); !ok || stats.RoundNumber != 3 {
+		t.Errorf("Expected the JSON block of 10.0.0.1:27015 to survive, got %+v", records[1])
+	}
+}
+
+func TestUDPReceiver_Serve(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("UDP not available: %v", err)
+	}
+
+	received := make(chan Record, 1)
+	u := &UDPReceiver{Handler: func(r Record) { received <- r }}
+
+	done := make(chan error)
+	go func() { done <- u.Serve(conn) }()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	client.Write(packet("", `L 08/31/2025 - 16:30:19.000: World triggered "Round_Start"`))
+
+	select {
+	case r := <-received:
+		if r.Err != nil || r.Message.GetType() != "WorldRoundStart" || r.Server != client.LocalAddr().String() {
+			t.Errorf("Unexpected record %+v", r)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("No record received")
+	}
+
+	conn.Close()
+	if err := <-done; err != nil {
+		t.Errorf("Expected nil after close, got %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package cs2log
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// WeaponCategory is the class of a weapon or item
+type WeaponCategory string
+
+// Categories of weapons and items
+const (
+	CategoryPistol WeaponCategory = "pistol"
+	CategorySMG    WeaponCategory = "smg"
+	CategoryRifle  WeaponCategory = "rifle"
+	CategorySniper WeaponCategory = "sniper"
+	// CategoryHeavy are shotguns and machine guns
+	CategoryHeavy     WeaponCategory = "heavy"
+	CategoryGrenade   WeaponCategory = "grenade"
+	CategoryKnife     WeaponCategory = "knife"
+	CategoryEquipment WeaponCategory = "equipment"
+)
+
+var (
+	// ErrorWeaponExists error when a weapon ID or alias is already registered
+	ErrorWeaponExists = errors.New("weapon already registered")
+)
+
+// Weapon describes a weapon or item
+type Weapon struct {
+	// ID is the canonical name, the name the log uses most, e.g. "ak47"
+	ID string `json:"id"`
+	// Name is the name shown in game, e.g. "AK-47"
+	Name     string         `json:"name"`
+	Category WeaponCategory `json:"category"`
+	// Side is the side that can buy the weapon, empty if both can
+	Side string `json:"side,omitempty"`
+	// Price is the price in the buy menu, 0 for items that can't be bought
+	Price int `json:"price"`
+	// Aliases are other names the log uses for the weapon, e.g. the names
+	// of variants like "m4a1_silencer_off"
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// WeaponRegistry looks up weapons by any of their names.
+// A registry is safe for concurrent use.
+type WeaponRegistry struct {
+	mu      sync.RWMutex
+	weapons map[string]Weapon
+	// ids maps IDs and aliases to IDs
+	ids map[string]string
+}
+
+// NewWeaponRegistry creates an empty registry
+func NewWeaponRegistry() *WeaponRegistry {
+	return &WeaponRegistry{
+		weapons: make(map[string]Weapon),
+		ids:     make(map[string]string),
+	}
+}
+
+// Register adds a weapon, neither its ID nor an alias may be registered yet
+func (r *WeaponRegistry) Register(w Weapon) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := append([]string{w.ID}, w.Aliases...)
+	for _, name := range names {
+		if _, ok := r.ids[name]; ok {
+			return ErrorWeaponExists
+		}
+	}
+
+	w.Aliases = append([]string(nil), w.Aliases...)
+	r.weapons[w.ID] = w
+	for _, name := range names {
+		r.ids[name] = w.ID
+	}
+
+	return nil
+}
+
+// Lookup returns the weapon with the given name. Names are normalized,
+// "weapon_ak47", "AK47" and "ak47" find the same weapon.
+func (r *WeaponRegistry) Lookup(name string) (Weapon, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	w, ok := r.weapons[r.id(name)]
+	return w, ok
+}
+
+// Normalize returns the ID of the weapon with the given name, unknown names
+// are returned in lower case without prefix
+func (r *WeaponRegistry) Normalize(name string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.id(name)
+}
+
+// Weapons returns all weapons sorted by ID
+func (r *WeaponRegistry) Weapons() []Weapon {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	weapons := make([]Weapon, 0, len(r.weapons))
+	for _, w := range r.weapons {
+		weapons = append(weapons, w)
+	}
+
+	sort.Slice(weapons, func(i, j int) bool {
+		return weapons[i].ID < weapons[j].ID
+	})
+
+	return weapons
+}
+
+// Prices returns the prices of all weapons by ID and alias
+func (r *WeaponRegistry) Prices() PriceTable {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	prices := make(PriceTable, len(r.ids))
+	for name, id := range r.ids {
+		prices[name] = r.weapons[id].Price
+	}
+
+	return prices
+}
+
+// Clone returns an independent copy of the registry
+func (r *WeaponRegistry) Clone() *WeaponRegistry {
+	c := NewWeaponRegistry()
+	for _, w := range r.Weapons() {
+		c.Register(w)
+	}
+	return c
+}
+
+// id normalizes a name and resolves aliases, knife skins all map to "knife"
+func (r *WeaponRegistry) id(name string) string {
+	name = equipmentName(strings.ToLower(strings.TrimSpace(name)))
+
+	if id, ok := r.ids[name]; ok {
+		return id
+	}
+
+	if _, ok := r.ids["knife"]; ok && (strings.HasPrefix(name, "knife") || name == "bayonet") {
+		return "knife"
+	}
+
+	return name
+}
+
+// builtinWeapons are the weapons and items of Counter-Strike 2
+var builtinWeapons = []Weapon{
+	{ID: "glock", Name: "Glock-18", Category: CategoryPistol, Side: "TERRORIST", Price: 200},
+	{ID: "hkp2000", Name: "P2000", Category: CategoryPistol, Side: "CT", Price: 200},
+	{ID: "usp_silencer", Name: "USP-S", Category: CategoryPistol, Side: "CT", Price: 200, Aliases: []string{"usp_silencer_off"}},
+	{ID: "p250", Name: "P250", Category: CategoryPistol, Price: 300},
+	{ID: "elite", Name: "Dual Berettas", Category: CategoryPistol, Price: 300},
+	{ID: "tec9", Name: "Tec-9", Category: CategoryPistol, Side: "TERRORIST", Price: 500},
+	{ID: "fiveseven", Name: "Five-SeveN", Category: CategoryPistol, Side: "CT", Price: 500},
+	{ID: "cz75a", Name: "CZ75-Auto", Category: CategoryPistol, Price: 500},
+	{ID: "deagle", Name: "Desert Eagle", Category: CategoryPistol, Price: 700},
+	{ID: "revolver", Name: "R8 Revolver", Category: CategoryPistol, Price: 600},
+
+	{ID: "mac10", Name: "MAC-10", Category: CategorySMG, Side: "TERRORIST", Price: 1050},
+	{ID: "mp9", Name: "MP9", Category: CategorySMG, Side: "CT", Price: 1250},
+	{ID: "mp7", Name: "MP7", Category: CategorySMG, Price: 1500},
+	{ID: "mp5sd", Name: "MP5-SD", Category: CategorySMG, Price: 1500},
+	{ID: "ump45", Name: "UMP-45", Category: CategorySMG, Price: 1200},
+	{ID: "p90", Name: "P90", Category: CategorySMG, Price: 2350},
+	{ID: "bizon", Name: "PP-Bizon", Category: CategorySMG, Price: 1400},
+
+	{ID: "nova", Name: "Nova", Category: CategoryHeavy, Price: 1050},
+	{ID: "xm1014", Name: "XM1014", Category: CategoryHeavy, Price: 2000},
+	{ID: "sawedoff", Name: "Sawed-Off", Category: CategoryHeavy, Side: "TERRORIST", Price: 1100},
+	{ID: "mag7", Name: "MAG-7", Category: CategoryHeavy, Side: "CT", Price: 1300},
+	{ID: "m249", Name: "M249", Category: CategoryHeavy, Price: 5200},
+	{ID: "negev", Name: "Negev", Category: CategoryHeavy, Price: 1700},
+
+	{ID: "galilar", Name: "Galil AR", Category: CategoryRifle, Side: "TERRORIST", Price: 1800},
+	{ID: "famas", Name: "FAMAS", Category: CategoryRifle, Side: "CT", Price: 2050},
+	{ID: "ak47", Name: "AK-47", Category: CategoryRifle, Side: "TERRORIST", Price: 2700},
+	{ID: "m4a1", Name: "M4A4", Category: CategoryRifle, Side: "CT", Price: 3100},
+	{ID: "m4a1_silencer", Name: "M4A1-S", Category: CategoryRifle, Side: "CT", Price: 2900, Aliases: []string{"m4a1_silencer_off"}},
+	{ID: "sg556", Name: "SG 553", Category: CategoryRifle, Side: "TERRORIST", Price: 3000},
+	{ID: "aug", Name: "AUG", Category: CategoryRifle, Side: "CT", Price: 3300},
+
+	{ID: "ssg08", Name: "SSG 08", Category: CategorySniper, Price: 1700},
+	{ID: "awp", Name: "AWP", Category: CategorySniper, Price: 4750},
+	{ID: "g3sg1", Name: "G3SG1", Category: CategorySniper, Side: "TERRORIST", Price: 5000},
+	{ID: "scar20", Name: "SCAR-20", Category: CategorySniper, Side: "CT", Price: 5000},
+
+	{ID: "hegrenade", Name: "High Explosive Grenade", Category: CategoryGrenade, Price: 300},
+	{ID: "flashbang", Name: "Flashbang", Category: CategoryGrenade, Price: 200, Aliases: []string{"flashgrenade"}},
+	{ID: "smokegrenade", Name: "Smoke Grenade", Category: CategoryGrenade, Price: 300},
+	{ID: "decoy", Name: "Decoy Grenade", Category: CategoryGrenade, Price: 50},
+	{ID: "molotov", Name: "Molotov", Category: CategoryGrenade, Side: "TERRORIST", Price: 400},
+	{ID: "incgrenade", Name: "Incendiary Grenade", Category: CategoryGrenade, Side: "CT", Price: 600},
+	// inferno is the fire of molotovs and incendiary grenades, the log names it as
+	// the weapon of kills and damage by fire
+	{ID: "inferno", Name: "Fire", Category: CategoryGrenade},
+
+	{ID: "knife", Name: "Knife", Category: CategoryKnife, Aliases: []string{"knife_t", "knifegg", "bayonet"}},
+
+	{ID: "kevlar", Name: "Kevlar Vest", Category: CategoryEquipment, Price: 650, Aliases: []string{"vest"}},
+	{ID: "assaultsuit", Name: "Kevlar + Helmet", Category: CategoryEquipment, Price: 1000, Aliases: []string{"vesthelm"}},
+	// helmet is listed separately from the vest when leaving the buy zone
+	{ID: "helmet", Name: "Helmet", Category: CategoryEquipment, Price: 350},
+	{ID: "defuser", Name: "Defuse Kit", Category: CategoryEquipment, Side: "CT", Price: 400, Aliases: []string{"cutters"}},
+	{ID: "taser", Name: "Zeus x27", Category: CategoryEquipment, Price: 200},
+	{ID: "c4", Name: "C4 Explosive", Category: CategoryEquipment, Side: "TERRORIST"},
+}
+
+// DefaultWeapons returns a new registry with the weapons and items of
+// Counter-Strike 2
+func DefaultWeapons() *WeaponRegistry {
+	r := NewWeaponRegistry()
+	for _, w := range builtinWeapons {
+		r.Register(w)
+	}
+	return r
+}
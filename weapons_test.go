@@ -0,0 +1,69 @@
+package cs2log
+
+import "testing"
+
+func TestWeaponRegistry(t *testing.T) {
+	r := DefaultWeapons()
+
+	tests := []struct {
+		name     string
+		id       string
+		category WeaponCategory
+		side     string
+		price    int
+	}{
+		{"ak47", "ak47", CategoryRifle, "TERRORIST", 2700},
+		{"weapon_AK47", "ak47", CategoryRifle, "TERRORIST", 2700},
+		{"m4a1_silencer_off", "m4a1_silencer", CategoryRifle, "CT", 2900},
+		{"weapon_knife_t", "knife", CategoryKnife, "", 0},
+		{"knife_karambit", "knife", CategoryKnife, "", 0},
+		{"inferno", "inferno", CategoryGrenade, "", 0},
+		{"hegrenade", "hegrenade", CategoryGrenade, "", 300},
+		{"item_assaultsuit", "assaultsuit", CategoryEquipment, "", 1000},
+		{"kevlar(100)", "kevlar", CategoryEquipment, "", 650},
+		{"awp", "awp", CategorySniper, "", 4750},
+		{"mag7", "mag7", CategoryHeavy, "CT", 1300},
+	}
+
+	for _, tt := range tests {
+		w, ok := r.Lookup(tt.name)
+		if !ok {
+			t.Errorf("%s: not found", tt.name)
+			continue
+		}
+		if w.ID != tt.id || w.Category != tt.category || w.Side != tt.side || w.Price != tt.price {
+			t.Errorf("%s: unexpected weapon %+v", tt.name, w)
+		}
+		if id := r.Normalize(tt.name); id != tt.id {
+			t.Errorf("%s: expected to normalize to %s, got %s", tt.name, tt.id, id)
+		}
+	}
+
+	if _, ok := r.Lookup("world"); ok {
+		t.Error("Expected world not to be a weapon")
+	}
+	if id := r.Normalize("weapon_Unknown"); id != "unknown" {
+		t.Errorf("Expected unknown names in lower case without prefix, got %s", id)
+	}
+}
+
+func TestWeaponRegistry_Register(t *testing.T) {
+	r := NewWeaponRegistry()
+
+	if err := r.Register(Weapon{ID: "knife", Category: CategoryKnife}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(Weapon{ID: "knife_t", Aliases: []string{"knife"}}); err != ErrorWeaponExists {
+		t.Errorf("Expected ErrorWeaponExists, got %v", err)
+	}
+
+	c := r.Clone()
+	c.Register(Weapon{ID: "taser", Category: CategoryEquipment, Price: 200})
+	if len(r.Weapons()) != 1 || len(c.Weapons()) != 2 {
+		t.Error("Expected the clone to be independent")
+	}
+
+	if prices := c.Prices(); prices["taser"] != 200 || prices["knife"] != 0 {
+		t.Errorf("Unexpected prices %v", prices)
+	}
+}